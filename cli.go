@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"multi-kind-app/internal/config"
+	"multi-kind-app/internal/storage"
+	"multi-kind-app/internal/storage/mongostore"
+	"multi-kind-app/internal/storage/redisstore"
+)
+
+// ──────────── Commands ────────────
+
+// newRootCmd wires serve, migrate and seed. Running the binary with no
+// subcommand serves, so existing deployments keep working unchanged.
+func newRootCmd() *cobra.Command {
+	var (
+		configFile string
+		cfg        *config.Config
+	)
+	root := &cobra.Command{
+		Use:           "multi-kind-app",
+		Short:         "Redis + Mongo + HTTP demo service",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if configFile != "" {
+				os.Setenv("CONFIG_FILE", configFile)
+			}
+			var err error
+			if cfg, err = config.Load(); err != nil {
+				return err
+			}
+			slog.SetDefault(newLogger(cfg.LogLevel, cfg.LogFormat))
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error { return serve(cfg) },
+	}
+	root.PersistentFlags().StringVar(&configFile, "config", "", "config file (.yaml, .yml or .json); overrides CONFIG_FILE")
+
+	root.AddCommand(
+		&cobra.Command{
+			Use:   "serve",
+			Short: "Run the HTTP server",
+			Args:  cobra.NoArgs,
+			RunE:  func(cmd *cobra.Command, args []string) error { return serve(cfg) },
+		},
+		&cobra.Command{
+			Use:   "migrate",
+			Short: "Apply pending Mongo schema migrations and exit",
+			Args:  cobra.NoArgs,
+			RunE:  func(cmd *cobra.Command, args []string) error { return runMigrate(cmd.Context(), cfg) },
+		},
+		&cobra.Command{
+			Use:   "seed",
+			Short: "Load the demo items into Mongo and Redis and exit",
+			Args:  cobra.NoArgs,
+			RunE:  func(cmd *cobra.Command, args []string) error { return runSeed(cmd.Context(), cfg) },
+		},
+	)
+	return root
+}
+
+// cliTimeout bounds a one-shot migrate or seed run.
+const cliTimeout = time.Minute
+
+// runMigrate applies the same migrations the server runs once Mongo
+// answers, but synchronously, so a deploy step can fail on them.
+func runMigrate(ctx context.Context, cfg *config.Config) error {
+	ctx, cancel := context.WithTimeout(ctx, cliTimeout)
+	defer cancel()
+	db, err := openMongo(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Client().Disconnect(context.Background())
+
+	applied, err := mongostore.Migrate(ctx, db)
+	if err != nil {
+		return fmt.Errorf("migrate (applied %v before failing): %w", applied, err)
+	}
+	version, err := mongostore.SchemaVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+	slog.Info("migrate done", "applied", applied, "schema_version", version)
+	return nil
+}
+
+// seedItems are the demo records. Their IDs are fixed, so seeding twice
+// leaves one copy of each.
+var seedItems = []storage.Item{
+	{ID: "demo-1", Name: "apple", Value: "red"},
+	{ID: "demo-2", Name: "banana", Value: "yellow"},
+	{ID: "demo-3", Name: "cherry", Value: "dark red"},
+	{ID: "demo-4", Name: "grape", Value: "purple"},
+	{ID: "demo-5", Name: "lime", Value: "green"},
+}
+
+// runSeed writes seedItems to every store through its ItemRepository.
+// Items already present are skipped rather than overwritten.
+func runSeed(ctx context.Context, cfg *config.Config) error {
+	ctx, cancel := context.WithTimeout(ctx, cliTimeout)
+	defer cancel()
+	db, err := openMongo(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Client().Disconnect(context.Background())
+	redisOpts, err := redisstore.Options(cfg)
+	if err != nil {
+		return fmt.Errorf("redis: %w", err)
+	}
+	rdb := redisstore.New(redisOpts, time.Duration(cfg.RedisTimeout))
+	defer rdb.Close()
+
+	col := db.Collection("items")
+	repos := []struct {
+		name string
+		repo storage.ItemRepository
+	}{
+		{"mongo", mongostore.NewItemRepo(func() (*mongo.Collection, error) { return col, nil })},
+		{"redis", redisstore.NewItemRepo(func() (*redis.Client, error) { return rdb, nil })},
+	}
+	for _, r := range repos {
+		created, skipped := 0, 0
+		for _, item := range seedItems {
+			_, err := r.repo.Create(ctx, item)
+			switch {
+			case errors.Is(err, storage.ErrConflict):
+				skipped++
+			case err != nil:
+				return fmt.Errorf("%s: seed %s: %w", r.name, item.ID, err)
+			default:
+				created++
+			}
+		}
+		slog.Info("seeded", "store", r.name, "created", created, "skipped", skipped)
+	}
+	return nil
+}
+
+func openMongo(cfg *config.Config) (*mongo.Database, error) {
+	opts, err := mongostore.Options(cfg, nil)
+	if err != nil {
+		return nil, fmt.Errorf("mongo: %w", err)
+	}
+	db, err := mongostore.Open(opts)
+	if err != nil {
+		return nil, fmt.Errorf("mongo: %w", err)
+	}
+	return db, nil
+}
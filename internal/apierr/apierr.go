@@ -0,0 +1,227 @@
+// Package apierr is the app's error model. Handlers return errors; this
+// package decides the status code and renders them as RFC 7807
+// application/problem+json bodies carrying a stable, machine-readable code.
+package apierr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"multi-kind-app/internal/service"
+	"multi-kind-app/internal/storage"
+)
+
+// ContentType is the media type of every error response.
+const ContentType = "application/problem+json"
+
+// Code is a stable error identifier. Clients should branch on it rather
+// than on the status code or the human-readable detail.
+type Code string
+
+const (
+	CodeBadRequest           Code = "BAD_REQUEST"
+	CodeInvalid              Code = "INVALID"
+	CodeUnauthorized         Code = "UNAUTHORIZED"
+	CodeForbidden            Code = "FORBIDDEN"
+	CodeNotFound             Code = "NOT_FOUND"
+	CodeConflict             Code = "CONFLICT"
+	CodeMediaType            Code = "UNSUPPORTED_MEDIA_TYPE"
+	CodePreconditionFailed   Code = "PRECONDITION_FAILED"
+	CodePreconditionRequired Code = "PRECONDITION_REQUIRED"
+	CodeRateLimited          Code = "RATE_LIMITED"
+	CodeInternal             Code = "INTERNAL"
+	CodeUpstream             Code = "UPSTREAM"
+	CodeUnavailable          Code = "UNAVAILABLE"
+	CodeOverloaded           Code = "OVERLOADED"
+	CodeTimeout              Code = "TIMEOUT"
+)
+
+// Error is an error with a status, a code and optional extension members.
+// Err, when set, is the cause and is what errors.Is and errors.As see.
+type Error struct {
+	Status int
+	Code   Code
+	Detail string
+	Extra  map[string]any
+	Err    error
+
+	// redacted is set by Wrap when Detail stands in for Err's text.
+	redacted bool
+}
+
+func (e *Error) Error() string { return e.Detail }
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// With adds an extension member to the problem body and returns e.
+func (e *Error) With(key string, v any) *Error {
+	if e.Extra == nil {
+		e.Extra = map[string]any{}
+	}
+	e.Extra[key] = v
+	return e
+}
+
+// New builds an Error with no cause.
+func New(status int, code Code, detail string) *Error {
+	return &Error{Status: status, Code: code, Detail: detail}
+}
+
+func BadRequest(detail string) *Error { return New(http.StatusBadRequest, CodeBadRequest, detail) }
+
+func Invalid(detail string) *Error { return New(http.StatusUnprocessableEntity, CodeInvalid, detail) }
+
+func NotFound(detail string) *Error { return New(http.StatusNotFound, CodeNotFound, detail) }
+
+func Conflict(detail string) *Error { return New(http.StatusConflict, CodeConflict, detail) }
+
+// Upstream wraps a failed outbound call as a 502.
+func Upstream(err error, op string) *Error {
+	return &Error{Status: http.StatusBadGateway, Code: CodeUpstream, Detail: op + ": " + err.Error(), Err: err}
+}
+
+// Wrap classifies err and prefixes its detail with op (e.g. "mongo" or
+// "redis SET"). An err that is already an *Error is returned unchanged.
+// Only this module's own errors keep their text: a driver error may carry
+// internals or connection strings, so its detail is generic ("internal
+// error" for a 500, "mongo find: timed out" and the like otherwise) and
+// Write logs the cause.
+func Wrap(err error, op string) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	status, code := classify(err)
+	if code == CodeInternal {
+		return &Error{Status: status, Code: code, Detail: "internal error", Err: err, redacted: true}
+	}
+	detail, redacted := err.Error(), !ownError(err)
+	if redacted {
+		detail = genericDetail(err, status, code)
+	}
+	if op != "" {
+		detail = op + ": " + detail
+	}
+	return &Error{Status: status, Code: code, Detail: detail, Err: err, redacted: redacted}
+}
+
+// ownError reports whether err's text was written by this module and is
+// safe to send. storage.ErrUnavailable is left out: it wraps the driver's
+// dial error.
+func ownError(err error) bool {
+	var invalid *service.InvalidError
+	return errors.As(err, &invalid) ||
+		errors.Is(err, storage.ErrInvalidQuery) ||
+		errors.Is(err, storage.ErrNotFound) ||
+		errors.Is(err, storage.ErrConflict) ||
+		errors.Is(err, service.ErrInvalidCredentials) ||
+		errors.Is(err, service.ErrUnknownStore)
+}
+
+// genericDetail describes a classified driver error without its text.
+func genericDetail(err error, status int, code Code) string {
+	switch {
+	case mongo.IsDuplicateKeyError(err):
+		return "duplicate key"
+	case redis.HasErrorPrefix(err, "WRONGTYPE"):
+		return "the key holds a different type"
+	case code == CodeTimeout:
+		return "timed out"
+	case code == CodeUnavailable:
+		return "unavailable"
+	case code == CodeNotFound:
+		return "not found"
+	}
+	return strings.ToLower(http.StatusText(status))
+}
+
+// From is Wrap without a prefix.
+func From(err error) *Error {
+	return Wrap(err, "")
+}
+
+// classify maps storage, validation and driver errors onto a status.
+// Anything unrecognised is a 500.
+func classify(err error) (int, Code) {
+	var invalid *service.InvalidError
+	var ne net.Error
+	switch {
+	case errors.Is(err, storage.ErrInvalidQuery):
+		return http.StatusBadRequest, CodeBadRequest
+	case errors.As(err, &invalid):
+		return http.StatusUnprocessableEntity, CodeInvalid
+	case errors.Is(err, service.ErrInvalidCredentials):
+		return http.StatusUnauthorized, CodeUnauthorized
+	case errors.Is(err, service.ErrUnknownStore),
+		errors.Is(err, storage.ErrNotFound),
+		errors.Is(err, mongo.ErrNoDocuments),
+		errors.Is(err, redis.Nil):
+		return http.StatusNotFound, CodeNotFound
+	case errors.Is(err, storage.ErrConflict), mongo.IsDuplicateKeyError(err),
+		redis.HasErrorPrefix(err, "WRONGTYPE"):
+		return http.StatusConflict, CodeConflict
+	case errors.Is(err, context.DeadlineExceeded), mongo.IsTimeout(err):
+		return http.StatusGatewayTimeout, CodeTimeout
+	case errors.Is(err, storage.ErrUnavailable), mongo.IsNetworkError(err), errors.As(err, &ne):
+		return http.StatusServiceUnavailable, CodeUnavailable
+	}
+	return http.StatusInternalServerError, CodeInternal
+}
+
+// ──────────── Problem Details ────────────
+
+// Problem is the RFC 7807 body. Extension members are flattened into the
+// top-level object alongside the standard ones.
+type Problem struct {
+	Type      string
+	Title     string
+	Status    int
+	Detail    string
+	Code      Code
+	Instance  string
+	RequestID string
+	Extra     map[string]any
+}
+
+func (p Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]any, len(p.Extra)+7)
+	for k, v := range p.Extra {
+		m[k] = v
+	}
+	m["type"] = p.Type
+	m["title"] = p.Title
+	m["status"] = p.Status
+	m["code"] = p.Code
+	if p.Detail != "" {
+		m["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		m["instance"] = p.Instance
+	}
+	if p.RequestID != "" {
+		m["request_id"] = p.RequestID
+	}
+	return json.Marshal(m)
+}
+
+// Problem renders e for the request at instance. Codes are the problem
+// type, so the type member stays about:blank and the title is the status
+// text, as RFC 7807 asks.
+func (e *Error) Problem(instance string) Problem {
+	return Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(e.Status),
+		Status:   e.Status,
+		Detail:   e.Detail,
+		Code:     e.Code,
+		Instance: instance,
+		Extra:    e.Extra,
+	}
+}
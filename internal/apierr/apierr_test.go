@@ -0,0 +1,73 @@
+package apierr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"multi-kind-app/internal/storage"
+)
+
+func TestWrapClassifies(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   Code
+	}{
+		{storage.ErrNotFound, http.StatusNotFound, CodeNotFound},
+		{fmt.Errorf("%w: sort", storage.ErrInvalidQuery), http.StatusBadRequest, CodeBadRequest},
+		{mongo.ErrNoDocuments, http.StatusNotFound, CodeNotFound},
+		{fmt.Errorf("insert: %w", storage.ErrConflict), http.StatusConflict, CodeConflict},
+		{storage.ErrUnavailable, http.StatusServiceUnavailable, CodeUnavailable},
+		{context.DeadlineExceeded, http.StatusGatewayTimeout, CodeTimeout},
+		{fmt.Errorf("boom"), http.StatusInternalServerError, CodeInternal},
+		{Conflict("taken"), http.StatusConflict, CodeConflict},
+	}
+	for _, tt := range tests {
+		e := Wrap(tt.err, "op")
+		if e.Status != tt.status || e.Code != tt.code {
+			t.Errorf("%v: got %d %s, want %d %s", tt.err, e.Status, e.Code, tt.status, tt.code)
+		}
+	}
+}
+
+func TestProblemFlattensExtensions(t *testing.T) {
+	p := NotFound("gone").With("dependency", "mongo").Problem("/x")
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]any
+	json.Unmarshal(b, &m)
+	if m["type"] != "about:blank" || m["title"] != "Not Found" || m["code"] != "NOT_FOUND" ||
+		m["instance"] != "/x" || m["dependency"] != "mongo" || m["status"] != float64(404) {
+		t.Fatalf("got %s", b)
+	}
+}
+
+func TestWrapHidesInternalCause(t *testing.T) {
+	cause := fmt.Errorf("dial mongodb://user:secret@db:27017: boom")
+	e := Wrap(cause, "mongo find")
+	if e.Detail != "internal error" || !errors.Is(e, cause) {
+		t.Fatalf("got detail %q, cause %v", e.Detail, e.Err)
+	}
+	if e := Wrap(storage.ErrNotFound, "mongo find"); e.Detail != "mongo find: item not found" {
+		t.Fatalf("non-internal detail: got %q", e.Detail)
+	}
+	for _, err := range []error{
+		fmt.Errorf("%w: dial tcp db:27017: connection refused", storage.ErrUnavailable),
+		fmt.Errorf("server selection: %w", context.DeadlineExceeded),
+	} {
+		e := Wrap(err, "mongo find")
+		if e.Status == http.StatusInternalServerError || strings.Contains(e.Detail, "db:27017") ||
+			strings.Contains(e.Detail, "server selection") {
+			t.Errorf("%v: got %d %q", err, e.Status, e.Detail)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package apierr
+
+import (
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ──────────── Gin ────────────
+
+// Write renders err as the response. The request id set by the requestID
+// middleware, when present, is echoed in the body. A cause Wrap kept out
+// of the detail is logged under that id rather than sent.
+func Write(c *gin.Context, err error) {
+	e := From(err)
+	if e.redacted {
+		level := slog.LevelWarn
+		if e.Code == CodeInternal {
+			level = slog.LevelError
+		}
+		slog.Log(c.Request.Context(), level, "request failed",
+			"request_id", c.GetString("request_id"),
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", e.Status,
+			"err", e.Err,
+		)
+	}
+	p := e.Problem(c.Request.URL.Path)
+	p.RequestID = c.GetString("request_id")
+	c.Header("Content-Type", ContentType)
+	c.JSON(e.Status, p)
+}
+
+// Abort writes err and stops the handler chain. Middleware uses it;
+// handlers call c.Error and return.
+func Abort(c *gin.Context, err error) {
+	Write(c, err)
+	c.Abort()
+}
+
+// Middleware renders the last error a handler attached with c.Error,
+// unless the handler already wrote a response.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		Write(c, c.Errors.Last().Err)
+	}
+}
@@ -0,0 +1,379 @@
+// Package config resolves the app's settings from defaults, an optional
+// JSON or YAML file (config.yaml unless CONFIG_FILE says otherwise) and
+// environment variables.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/x/mongo/driver/connstring"
+	"gopkg.in/yaml.v3"
+)
+
+// ──────────── Configuration ────────────
+
+// Duration is a time.Duration that reads as a Go duration string ("5s")
+// from JSON, YAML and env vars alike.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalText(b []byte) error {
+	v, err := time.ParseDuration(string(b))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// DefaultFile is read when CONFIG_FILE is unset. It is optional: a missing
+// file just means defaults and env vars.
+const DefaultFile = "config.yaml"
+
+// Config holds every connection setting and tunable. Values are resolved
+// in order: defaults, then the config file, then env vars.
+type Config struct {
+	Port string `json:"port" yaml:"port"`
+
+	MongoURI         string `json:"mongo_uri" yaml:"mongo_uri"`
+	MongoSocket      string `json:"mongo_socket" yaml:"mongo_socket"`
+	MongoMaxPoolSize int    `json:"mongo_max_pool_size" yaml:"mongo_max_pool_size"`
+	MongoReplicaSet  string `json:"mongo_replica_set" yaml:"mongo_replica_set"`
+	FallbackMemory   bool   `json:"fallback_memory" yaml:"fallback_memory"`
+
+	RedisAddr     string `json:"redis_addr" yaml:"redis_addr"`
+	RedisSocket   string `json:"redis_socket" yaml:"redis_socket"`
+	RedisReadAddr string `json:"redis_read_addr" yaml:"redis_read_addr"`
+	RedisPoolSize int    `json:"redis_pool_size" yaml:"redis_pool_size"`
+
+	UpstreamURL         string `json:"upstream_url" yaml:"upstream_url"`
+	HTTPRetries         int    `json:"http_retries" yaml:"http_retries"`
+	DisableOutboundHTTP bool   `json:"disable_outbound_http" yaml:"disable_outbound_http"`
+	WebhookURL          string `json:"webhook_url" yaml:"webhook_url"`
+
+	StartupAttempts   int      `json:"startup_attempts" yaml:"startup_attempts"`
+	ReconnectInterval Duration `json:"reconnect_interval" yaml:"reconnect_interval"`
+
+	RequestTimeout Duration `json:"request_timeout" yaml:"request_timeout"`
+	RedisTimeout   Duration `json:"redis_timeout" yaml:"redis_timeout"`
+	MongoTimeout   Duration `json:"mongo_timeout" yaml:"mongo_timeout"`
+	HTTPTimeout    Duration `json:"http_timeout" yaml:"http_timeout"`
+
+	MaxConcurrent int64 `json:"max_concurrent" yaml:"max_concurrent"`
+	MaxPageSize   int64 `json:"max_page_size" yaml:"max_page_size"`
+	MaxBulkItems  int   `json:"max_bulk_items" yaml:"max_bulk_items"`
+	// IDMode is how server-side ids are minted: "objectid" (time-ordered)
+	// or "uuid" (random v4, so consecutive ids don't reveal their order).
+	IDMode        string   `json:"id_mode" yaml:"id_mode"`
+	ItemCacheSize int      `json:"item_cache_size" yaml:"item_cache_size"`
+	SessionTTL    Duration `json:"session_ttl" yaml:"session_ttl"`
+	ThrottleRPS   float64  `json:"throttle_rps" yaml:"throttle_rps"`
+	ThrottleBurst int      `json:"throttle_burst" yaml:"throttle_burst"`
+
+	Backends         []string `json:"backends" yaml:"backends"`
+	AdminAPIKey      string   `json:"admin_api_key" yaml:"admin_api_key"`
+	CriticalBackends []string `json:"critical_backends" yaml:"critical_backends"`
+	SnapshotRoutes   []string `json:"snapshot_routes" yaml:"snapshot_routes"`
+
+	ContentHash         bool `json:"content_hash" yaml:"content_hash"`
+	ContentHashMaxBytes int  `json:"content_hash_max_bytes" yaml:"content_hash_max_bytes"`
+
+	LogLevel  string `json:"log_level" yaml:"log_level"`
+	LogFormat string `json:"log_format" yaml:"log_format"`
+}
+
+// Default returns the built-in settings.
+func Default() *Config {
+	return &Config{
+		Port:                "8080",
+		MongoURI:            "mongodb://mongodb-svc:27017",
+		RedisAddr:           "redis-svc:6379",
+		UpstreamURL:         "https://jsonplaceholder.typicode.com/todos/1",
+		HTTPRetries:         3,
+		StartupAttempts:     5,
+		ReconnectInterval:   Duration(30 * time.Second),
+		RequestTimeout:      Duration(10 * time.Second),
+		MaxPageSize:         100,
+		MaxBulkItems:        500,
+		IDMode:              "objectid",
+		ItemCacheSize:       128,
+		SessionTTL:          Duration(30 * time.Minute),
+		ThrottleRPS:         5,
+		ThrottleBurst:       10,
+		ContentHashMaxBytes: 1 << 20,
+		Backends:            []string{"redis", "mongo", "http"},
+		CriticalBackends:    []string{"redis", "mongo", "http"},
+		LogLevel:            "info",
+		LogFormat:           "json",
+	}
+}
+
+// Load resolves the configuration and rejects values that would
+// otherwise be silently replaced by defaults.
+func Load() (*Config, error) {
+	c := Default()
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := c.loadFile(path); err != nil {
+			return nil, fmt.Errorf("CONFIG_FILE %s: %w", path, err)
+		}
+	} else if err := c.loadFile(DefaultFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%s: %w", DefaultFile, err)
+	}
+	if err := c.applyEnv(); err != nil {
+		return nil, err
+	}
+	// Per-backend timeouts inherit the request timeout when left unset.
+	for _, d := range []*Duration{&c.RedisTimeout, &c.MongoTimeout, &c.HTTPTimeout} {
+		if *d == 0 {
+			*d = c.RequestTimeout
+		}
+	}
+	return c, c.validate()
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return json.Unmarshal(data, c)
+	case ".yaml", ".yml":
+		return yaml.Unmarshal(data, c)
+	default:
+		return fmt.Errorf("unsupported extension %q (want .json, .yaml or .yml)", filepath.Ext(path))
+	}
+}
+
+// applyEnv overrides file and default values with any env vars that are set.
+func (c *Config) applyEnv() error {
+	str := func(dst *string, key string) {
+		if v := os.Getenv(key); v != "" {
+			*dst = v
+		}
+	}
+	list := func(dst *[]string, key string) {
+		if v := os.Getenv(key); v != "" {
+			*dst = splitList(v)
+		}
+	}
+	str(&c.Port, "PORT")
+	str(&c.MongoURI, "MONGO_URI")
+	str(&c.MongoSocket, "MONGO_SOCKET")
+	str(&c.MongoReplicaSet, "MONGO_REPLICA_SET")
+	str(&c.RedisAddr, "REDIS_ADDR")
+	str(&c.RedisSocket, "REDIS_SOCKET")
+	str(&c.RedisReadAddr, "REDIS_READ_ADDR")
+	str(&c.UpstreamURL, "UPSTREAM_URL")
+	str(&c.WebhookURL, "WEBHOOK_URL")
+	str(&c.AdminAPIKey, "ADMIN_API_KEY")
+	str(&c.LogLevel, "LOG_LEVEL")
+	str(&c.LogFormat, "LOG_FORMAT")
+	str(&c.IDMode, "ID_MODE")
+	list(&c.Backends, "BACKENDS")
+	list(&c.CriticalBackends, "CRITICAL_BACKENDS")
+	list(&c.SnapshotRoutes, "SNAPSHOT_ROUTES")
+
+	var errs []string
+	parse := func(key string, fn func(string) error) {
+		if v := os.Getenv(key); v != "" {
+			if err := fn(v); err != nil {
+				errs = append(errs, fmt.Sprintf("%s=%q: %v", key, v, err))
+			}
+		}
+	}
+	boolVar := func(dst *bool) func(string) error {
+		return func(v string) (err error) { *dst, err = strconv.ParseBool(v); return }
+	}
+	intVar := func(dst *int) func(string) error {
+		return func(v string) (err error) { *dst, err = strconv.Atoi(v); return }
+	}
+	int64Var := func(dst *int64) func(string) error {
+		return func(v string) (err error) { *dst, err = strconv.ParseInt(v, 10, 64); return }
+	}
+	floatVar := func(dst *float64) func(string) error {
+		return func(v string) (err error) { *dst, err = strconv.ParseFloat(v, 64); return }
+	}
+	durVar := func(dst *Duration) func(string) error {
+		return func(v string) error { return dst.UnmarshalText([]byte(v)) }
+	}
+	parse("FALLBACK_MEMORY", boolVar(&c.FallbackMemory))
+	parse("DISABLE_OUTBOUND_HTTP", boolVar(&c.DisableOutboundHTTP))
+	parse("CONTENT_HASH", boolVar(&c.ContentHash))
+	parse("HTTP_RETRIES", intVar(&c.HTTPRetries))
+	parse("STARTUP_ATTEMPTS", intVar(&c.StartupAttempts))
+	parse("CONTENT_HASH_MAX_BYTES", intVar(&c.ContentHashMaxBytes))
+	parse("ITEM_CACHE_SIZE", intVar(&c.ItemCacheSize))
+	parse("MAX_BULK_ITEMS", intVar(&c.MaxBulkItems))
+	parse("REDIS_POOL_SIZE", intVar(&c.RedisPoolSize))
+	parse("MONGO_MAX_POOL_SIZE", intVar(&c.MongoMaxPoolSize))
+	parse("THROTTLE_BURST", intVar(&c.ThrottleBurst))
+	parse("MAX_CONCURRENT", int64Var(&c.MaxConcurrent))
+	parse("MAX_PAGE_SIZE", int64Var(&c.MaxPageSize))
+	parse("THROTTLE_RPS", floatVar(&c.ThrottleRPS))
+	parse("REQUEST_TIMEOUT", durVar(&c.RequestTimeout))
+	parse("REDIS_TIMEOUT", durVar(&c.RedisTimeout))
+	parse("MONGO_TIMEOUT", durVar(&c.MongoTimeout))
+	parse("HTTP_TIMEOUT", durVar(&c.HTTPTimeout))
+	parse("SESSION_TTL", durVar(&c.SessionTTL))
+	parse("RECONNECT_INTERVAL", durVar(&c.ReconnectInterval))
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid environment: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (c *Config) validate() error {
+	var errs []string
+	check := func(ok bool, msg string) {
+		if !ok {
+			errs = append(errs, msg)
+		}
+	}
+	check(c.Port != "", "port must be set")
+	if n, err := strconv.Atoi(c.Port); c.Port != "" && (err != nil || n < 1 || n > 65535) {
+		errs = append(errs, fmt.Sprintf("port %q must be a number between 1 and 65535", c.Port))
+	}
+	if c.MongoSocket == "" {
+		if err := checkMongoURI(c.MongoURI); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if c.RedisSocket == "" {
+		if err := checkAddr("redis_addr", c.RedisAddr); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if c.RedisReadAddr != "" {
+		if err := checkAddr("redis_read_addr", c.RedisReadAddr); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if err := checkHTTPURL("upstream_url", c.UpstreamURL); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if c.WebhookURL != "" {
+		if err := checkHTTPURL("webhook_url", c.WebhookURL); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	check(c.HTTPRetries >= 1, "http_retries must be >= 1")
+	check(c.StartupAttempts >= 1, "startup_attempts must be >= 1")
+	check(c.MaxConcurrent >= 0, "max_concurrent must be >= 0 (0 = unlimited)")
+	check(c.MaxPageSize > 0, "max_page_size must be > 0")
+	check(c.MaxBulkItems > 0, "max_bulk_items must be > 0")
+	check(c.IDMode == "objectid" || c.IDMode == "uuid", "id_mode must be objectid or uuid")
+	check(c.ItemCacheSize >= 0, "item_cache_size must be >= 0 (0 = disabled)")
+	check(c.SessionTTL > 0, "session_ttl must be > 0")
+	check(c.ThrottleRPS > 0, "throttle_rps must be > 0")
+	check(c.ThrottleBurst > 0, "throttle_burst must be > 0")
+	check(c.ContentHashMaxBytes > 0, "content_hash_max_bytes must be > 0")
+	check(c.RedisPoolSize >= 0, "redis_pool_size must be >= 0 (0 = driver default)")
+	check(c.MongoMaxPoolSize >= 0, "mongo_max_pool_size must be >= 0 (0 = driver default)")
+	for name, d := range map[string]Duration{
+		"request_timeout":    c.RequestTimeout,
+		"redis_timeout":      c.RedisTimeout,
+		"mongo_timeout":      c.MongoTimeout,
+		"http_timeout":       c.HTTPTimeout,
+		"reconnect_interval": c.ReconnectInterval,
+	} {
+		check(d > 0, name+" must be > 0")
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// checkMongoURI parses a mongodb:// URI the way the driver will, so a typo
+// fails at startup instead of on the first query. mongodb+srv:// URIs are
+// only checked for a host, since resolving them needs DNS.
+func checkMongoURI(uri string) error {
+	fail := func(reason string) error {
+		return fmt.Errorf("mongo_uri %q: %s (want mongodb://host:port[/db][?opts] or mongodb+srv://host)", redact(uri), reason)
+	}
+	switch {
+	case uri == "":
+		return fail("must be set")
+	case strings.HasPrefix(uri, "mongodb+srv://"):
+		u, err := url.Parse(uri)
+		if err != nil {
+			return fail(err.Error())
+		}
+		if u.Hostname() == "" {
+			return fail("missing host")
+		}
+	case strings.HasPrefix(uri, "mongodb://"):
+		if _, err := connstring.ParseAndValidate(uri); err != nil {
+			return fail(err.Error())
+		}
+	default:
+		return fail("unknown scheme")
+	}
+	return nil
+}
+
+// checkAddr requires a host:port pair with a numeric port.
+func checkAddr(name, addr string) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err == nil && host == "" {
+		err = errors.New("missing host")
+	}
+	if err == nil {
+		if n, perr := strconv.Atoi(port); perr != nil || n < 1 || n > 65535 {
+			err = fmt.Errorf("bad port %q", port)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%s %q: %v (want host:port, e.g. redis-svc:6379)", name, addr, err)
+	}
+	return nil
+}
+
+// checkHTTPURL requires an absolute http or https URL.
+func checkHTTPURL(name, raw string) error {
+	u, err := url.Parse(raw)
+	if err == nil && (u.Scheme != "http" && u.Scheme != "https" || u.Host == "") {
+		err = errors.New("not an absolute http(s) URL")
+	}
+	if err != nil {
+		return fmt.Errorf("%s %q: %v", name, redact(raw), err)
+	}
+	return nil
+}
+
+// redact hides the userinfo of a URI so passwords never reach the logs.
+func redact(uri string) string {
+	scheme, rest, ok := strings.Cut(uri, "://")
+	if !ok {
+		return uri
+	}
+	if at := strings.LastIndex(rest, "@"); at >= 0 {
+		return scheme + "://***@" + rest[at+1:]
+	}
+	return uri
+}
+
+func splitList(v string) []string {
+	out := []string{}
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
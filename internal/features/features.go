@@ -0,0 +1,81 @@
+// Package features holds the per-backend feature flags. A backend that is
+// off at startup has no routes at all; one switched off at runtime keeps
+// its routes but they answer 503 until it is switched back on.
+package features
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrUnknown is returned for a name New was not told about.
+	ErrUnknown = errors.New("unknown backend")
+	// ErrRestartRequired is returned when enabling a backend that was off
+	// at startup: its routes were never registered.
+	ErrRestartRequired = errors.New("backend was disabled at startup; enable it in config and restart")
+)
+
+// Flags is safe for concurrent use.
+type Flags struct {
+	mu      sync.RWMutex
+	enabled map[string]bool
+	startup map[string]bool
+}
+
+// New has a flag for each of known and enables exactly the enabled ones.
+// Unknown names are logged and ignored.
+func New(known, enabled []string) *Flags {
+	f := &Flags{enabled: map[string]bool{}, startup: map[string]bool{}}
+	for _, name := range known {
+		f.enabled[name] = false
+	}
+	for _, name := range enabled {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := f.enabled[name]; !ok {
+			slog.Warn("BACKENDS: unknown backend ignored", "backend", name)
+			continue
+		}
+		f.enabled[name], f.startup[name] = true, true
+	}
+	return f
+}
+
+// Enabled reports whether every named backend is on.
+func (f *Flags) Enabled(names ...string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for _, name := range names {
+		if !f.enabled[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// Set switches a backend on or off at runtime.
+func (f *Flags) Set(name string, on bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.enabled[name]; !ok {
+		return ErrUnknown
+	}
+	if on && !f.startup[name] {
+		return ErrRestartRequired
+	}
+	f.enabled[name] = on
+	return nil
+}
+
+// Snapshot returns every backend's current state.
+func (f *Flags) Snapshot() map[string]bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	out := make(map[string]bool, len(f.enabled))
+	for name, on := range f.enabled {
+		out[name] = on
+	}
+	return out
+}
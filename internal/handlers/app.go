@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/sync/singleflight"
+
+	"multi-kind-app/internal/config"
+	"multi-kind-app/internal/features"
+	"multi-kind-app/internal/service"
+	"multi-kind-app/internal/storage"
+	"multi-kind-app/internal/storage/memstore"
+	"multi-kind-app/internal/storage/mongostore"
+	"multi-kind-app/internal/storage/redisstore"
+)
+
+// App holds everything the handlers share: the configuration, the lazily
+// built backend clients and the in-process state (caches, counters,
+// histograms). Handlers are methods on it, so two Apps never see each
+// other's state.
+type App struct {
+	cfg   *config.Config
+	flags *features.Flags
+
+	// backends holds one instance of every registered Backend, by name;
+	// backendNames is the same names sorted.
+	backends     map[string]Backend
+	backendNames []string
+
+	// Clients are built on first use rather than in main, so the server
+	// starts listening immediately and a backend that comes up late is
+	// simply picked up by the drivers' own lazy dialing. Only configuration
+	// problems (a bad URI, a missing socket) fail initialisation; that error
+	// is cached and every caller gets a 503.
+	//
+	// clientsMu guards the client pointers below against a concurrent
+	// handleResetPool swap.
+	clientsMu sync.RWMutex
+
+	redisOnce    sync.Once
+	redisErr     error
+	redisPrimary *redis.Client
+	// redisRead serves read-only handlers. It points at REDIS_READ_ADDR when
+	// set (e.g. a replica) and is the same client as redisPrimary otherwise.
+	redisRead *redis.Client
+
+	mongoOnce sync.Once
+	mongoErr  error
+	mongoDB   *mongo.Database
+	itemsCol  *mongo.Collection
+	// mongoOpts is kept from initMongo so the client can be rebuilt
+	// identically.
+	mongoOpts *options.ClientOptions
+
+	// resetMu serialises resets so two callers never rebuild the same client
+	// concurrently. The swap itself happens under clientsMu.
+	resetMu sync.Mutex
+
+	// httpClient is shared by all outbound calls. Its transport records
+	// traced calls and honours injected HTTP failures.
+	httpClient *http.Client
+
+	memItems *memstore.Store
+	// cache holds GET /api/item/:id results.
+	cache *service.CacheService
+	// itemReads coalesces concurrent getItem lookups, keyed by backend+id.
+	itemReads singleflight.Group
+	// itemRepos maps the :store route parameter to its repository; items
+	// serves them, and the handlers only ever go through items.
+	itemRepos map[string]storage.ItemRepository
+	items     *service.ItemService
+	userRepo  storage.UserRepository
+	users     *service.UserService
+
+	// pingHistory keeps the last error and last success of every backend
+	// ping, guarded by pingsMu.
+	pingsMu     sync.Mutex
+	pingHistory map[string]pingHistory
+
+	// deps is the last seen availability of each guarded backend.
+	deps map[string]*depState
+
+	// injected holds, per backend, how many upcoming operations should fail.
+	injected map[string]*atomic.Int64
+
+	inFlight atomic.Int64
+	rejected atomic.Int64
+	// latencies holds one histogram per "METHOD route" key.
+	latencies sync.Map // string → *histogram
+
+	// snapshotRoutes are the route patterns (as registered, e.g.
+	// /api/item/:id) whose responses get recorded.
+	snapshotRoutes map[string]bool
+	// criticalBackends are the backends whose failure makes /readyz return
+	// 503. Others are still pinged and reported but are informational only.
+	criticalBackends map[string]bool
+
+	// stop is closed by Close and ends background loops, such as each
+	// throttle's eviction, that outlive a request.
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// Option overrides part of what NewApp builds, mainly so tests can run
+// the router against fakes.
+type Option func(a *App)
+
+// WithItemRepo serves /stores/{name}/items from repo.
+func WithItemRepo(name string, repo storage.ItemRepository) Option {
+	return func(a *App) { a.itemRepos[name] = repo }
+}
+
+// WithUserRepo stores /users in repo instead of Mongo.
+func WithUserRepo(repo storage.UserRepository) Option {
+	return func(a *App) { a.userRepo = repo }
+}
+
+// WithHTTPTransport sends outbound HTTP through rt instead of the network.
+// Tracing and fault injection still wrap it.
+func WithHTTPTransport(rt http.RoundTripper) Option {
+	return func(a *App) {
+		a.httpClient.Transport = traceTransport{base: faultTransport{app: a, base: rt}}
+	}
+}
+
+// NewApp builds an App for c. Backend clients are not created until first
+// use.
+func NewApp(c *config.Config, opts ...Option) *App {
+	a := &App{
+		cfg:      c,
+		backends: map[string]Backend{},
+		memItems: memstore.New(),
+		cache:    service.NewCacheService(c.ItemCacheSize),
+		injected: map[string]*atomic.Int64{
+			"redis": new(atomic.Int64),
+			"mongo": new(atomic.Int64),
+			"http":  new(atomic.Int64),
+		},
+		snapshotRoutes: map[string]bool{},
+		deps:           map[string]*depState{},
+		pingHistory:    map[string]pingHistory{},
+		stop:           make(chan struct{}),
+	}
+	a.backendNames = registeredNames()
+	for _, name := range a.backendNames {
+		a.backends[name] = registry[name](a)
+		a.deps[name] = &depState{}
+	}
+	a.flags = features.New(a.backendNames, c.Backends)
+	a.httpClient = &http.Client{
+		Timeout:   time.Duration(c.HTTPTimeout),
+		Transport: traceTransport{base: faultTransport{app: a, base: http.DefaultTransport}},
+	}
+	a.itemRepos = onlyEnabled(a.flags, map[string]storage.ItemRepository{
+		"mongo": mongostore.NewItemRepo(a.getItemsCol),
+		"redis": redisstore.NewItemRepo(a.getRedis),
+	})
+	a.userRepo = mongostore.NewUserRepo(a.getMongo)
+	for _, route := range c.SnapshotRoutes {
+		a.snapshotRoutes[route] = true
+	}
+	a.criticalBackends = a.criticalSet(c.CriticalBackends)
+	for _, opt := range opts {
+		opt(a)
+	}
+	newID := service.IDFuncFor(c.IDMode)
+	a.items = service.NewItemService(a.itemRepos, newID)
+	a.users = service.NewUserService(a.userRepo, bcrypt.DefaultCost, newID)
+	return a
+}
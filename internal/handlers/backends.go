@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"multi-kind-app/internal/storage/mongostore"
+)
+
+// ──────────── Built-in Backends ────────────
+
+func init() {
+	Register("redis", func(a *App) Backend { return redisBackend{a} })
+	Register("mongo", func(a *App) Backend { return mongoBackend{a} })
+	Register("http", func(a *App) Backend { return httpBackend{a} })
+}
+
+// Both datastores connect at startup; see App.Connect.
+var (
+	_ Connector = redisBackend{}
+	_ Connector = mongoBackend{}
+)
+
+type redisBackend struct{ app *App }
+
+func (b redisBackend) Ping(ctx context.Context) error {
+	rdb, err := b.app.getRedis()
+	if err != nil {
+		return err
+	}
+	return rdb.Ping(ctx).Err()
+}
+
+// Connected makes Redis a Connector so App.Connect retries it at startup
+// like Mongo. Nothing needs to run once it answers.
+func (redisBackend) Connected(context.Context) error { return nil }
+
+func (b redisBackend) Routes(rt Routes) {
+	a, up := b.app, rt.Guard
+	rt.Root.GET("/redis/:val", up, a.handleRedisOnly) // ONLY Redis → Kind: "Redis"
+	rt.Throttled.GET("/redis/:val", up, a.handleRedisOnly)
+
+	rt.Root.DELETE("/redis/prefix/:prefix", up, a.handleScanDelete)  // SCAN + UNLINK cleanup
+	rt.Root.POST("/redis/bitmap/:key", up, a.handleBitmap)           // SETBIT
+	rt.Root.GET("/redis/bitmap/:key/count", up, a.handleBitmapCount) // BITCOUNT
+	rt.Root.POST("/lease/:name", up, a.handleLeaseCreate)            // key + logical expiry
+
+	// Generic key CRUD
+	rt.Root.PUT("/redis/keys/:key", up, a.handleKeyPut)       // SET with optional TTL
+	rt.Root.GET("/redis/keys/:key", up, a.handleKeyGet)       // GET + TTL
+	rt.Root.HEAD("/redis/keys/:key", up, a.handleKeyExists)   // EXISTS
+	rt.Root.DELETE("/redis/keys/:key", up, a.handleKeyDelete) // DEL
+
+	rt.Root.POST("/api/item/:id/demote", up, a.handleDemote)         // Redis hot tier removal
+	rt.Root.GET("/api/items/by-name/:name", up, a.handleIndexLookup) // Redis index only
+
+	// Session store — create/read/expire lifecycle over Redis
+	rt.Root.POST("/session", up, a.handleSessionCreate)
+	rt.Root.GET("/session/:id", up, a.handleSessionGet)
+	rt.Root.DELETE("/session/:id", up, a.handleSessionDelete)
+
+	rt.Root.GET("/debug/pool", a.handlePoolStats)
+	if rt.Admin != nil {
+		rt.Admin.POST("/reap", up, a.handleReap)
+	}
+}
+
+type mongoBackend struct{ app *App }
+
+// Ping honours injected Mongo failures, so /admin/inject shows up in the
+// health checks.
+func (b mongoBackend) Ping(ctx context.Context) error {
+	mdb, err := b.app.getMongo()
+	if err != nil {
+		return err
+	}
+	if err := b.app.injectedFault("mongo"); err != nil {
+		return err
+	}
+	return mdb.RunCommand(ctx, bson.D{{Key: "ping", Value: 1}}).Err()
+}
+
+// Connected applies pending migrations once Mongo is reachable.
+func (b mongoBackend) Connected(ctx context.Context) error {
+	mdb, err := b.app.getMongo()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	if _, err := mongostore.Migrate(ctx, mdb); err != nil {
+		slog.Error("schema migrations failed", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (b mongoBackend) Routes(rt Routes) {
+	a, up := b.app, rt.Guard
+	rt.Root.GET("/mongo/:val", up, a.handleMongoOnly) // ONLY Mongo → Kind: "Mongo"
+	rt.Throttled.GET("/mongo/:val", up, a.handleMongoOnly)
+
+	rt.Root.POST("/mongo/items", up, a.handleMongoInsert)       // Mongo InsertOne
+	rt.Root.GET("/mongo/items/:id", up, a.handleMongoGet)       // Mongo read by ObjectID
+	rt.Root.PUT("/mongo/items/:id", up, a.handleMongoReplace)   // Mongo ReplaceOne
+	rt.Root.PATCH("/mongo/items/:id", up, a.handleMongoUpdate)  // Mongo merge patch, $set/$unset
+	rt.Root.DELETE("/mongo/items/:id", up, a.handleMongoDelete) // Mongo DeleteOne
+	rt.Root.POST("/mongo/tx", up, a.handleMongoTx)              // Mongo multi-document transaction
+	rt.Root.POST("/gridfs", up, a.handleGridFSUpload)           // Mongo GridFS upload
+	rt.Root.GET("/gridfs/:id", up, a.handleGridFSDownload)      // Mongo GridFS chunked read
+
+	rt.Root.GET("/api/items/batch", up, a.getItems)                // Mongo, multi-id fetch
+	rt.Root.GET("/api/items/random", up, a.handleRandomItem)       // Mongo $sample
+	rt.Root.GET("/api/items", up, a.listItems)                     // Mongo, paginated
+	rt.Root.GET("/feed", up, a.handleFeed)                         // Mongo, keyset paginated
+	rt.Root.POST("/api/items/ingest", up, a.ingestItems)           // Mongo, NDJSON stream
+	rt.Root.POST("/api/item/:id/duplicate", up, a.handleDuplicate) // Mongo read + insert
+	rt.Root.POST("/api/item/:id/touch", up, a.handleTouch)         // Mongo $set updated_at
+
+	rt.Root.GET("/snapshots/*route", up, a.handleSnapshots)
+
+	// Users — bcrypt-hashed passwords in the users collection
+	rt.Root.POST("/users/register", up, a.handleRegister)
+	rt.Root.POST("/users/login", up, a.handleLogin)
+
+	if rt.Admin != nil {
+		rt.Admin.GET("/backup", up, a.handleBackup)
+		rt.Admin.POST("/restore", up, a.handleRestore)
+		rt.Admin.POST("/migrate", a.handleMigrate)
+	}
+}
+
+type httpBackend struct{ app *App }
+
+func (b httpBackend) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, b.app.cfg.UpstreamURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := b.app.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (b httpBackend) Routes(rt Routes) {
+	a, up := b.app, rt.Guard
+	rt.Root.GET("/http", up, a.handleHTTPOnly) // ONLY HTTP  → Kind: "Http"
+	rt.Throttled.GET("/http", up, a.handleHTTPOnly)
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"multi-kind-app/internal/apierr"
+	"multi-kind-app/internal/service"
+)
+
+// ──────────── Backup / Restore ────────────
+
+// backupDump is the backup format. Items are MongoDB canonical Extended
+// JSON, so ObjectIDs, dates and number types survive the round trip.
+// Complete is written last and is false when the backup stopped early, in
+// which case Error says why and restore refuses the dump.
+type backupDump struct {
+	CreatedAt time.Time         `json:"created_at"`
+	Items     []json.RawMessage `json:"items"`
+	Complete  bool              `json:"complete"`
+	Error     string            `json:"error,omitempty"`
+}
+
+// handleBackup — streams every document, all fields, as one JSON document.
+// Documents are encoded one at a time straight off the cursor, so the dump
+// is never buffered.
+func (a *App) handleBackup(c *gin.Context) {
+	col, err := a.getItemsCol()
+	if err != nil {
+		unavailable(c, "mongo", err)
+		return
+	}
+	ctx := c.Request.Context()
+	if err := a.injectedFault("mongo"); err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	cur, err := col.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	defer cur.Close(ctx)
+
+	c.Header("Content-Type", "application/json")
+	c.Header("Content-Disposition", `attachment; filename="backup.json"`)
+	c.Status(200)
+
+	w := c.Writer
+	fmt.Fprintf(w, `{"created_at":%q,"items":[`, time.Now().UTC().Format(time.RFC3339))
+	for n := 0; cur.Next(ctx); n++ {
+		var doc []byte
+		if doc, err = bson.MarshalExtJSON(cur.Current, true, false); err != nil {
+			err = fmt.Errorf("encode document %d: %w", n, err)
+			break
+		}
+		if n > 0 {
+			w.WriteString(",")
+		}
+		w.Write(doc)
+	}
+	if err == nil {
+		err = cur.Err()
+	}
+	if err != nil {
+		// Headers are gone, so the trailer is the only way to tell the
+		// client the dump is short.
+		reqLog(c).Error("backup", "err", err)
+		msg, _ := json.Marshal(apierr.Wrap(err, "mongo").Detail)
+		fmt.Fprintf(w, `],"complete":false,"error":%s}`, msg)
+		return
+	}
+	w.WriteString(`],"complete":true}`)
+}
+
+// handleRestore — upserts every document from a complete backup dump in one
+// bulk write, keyed by its _id as it was stored.
+func (a *App) handleRestore(c *gin.Context) {
+	col, err := a.getItemsCol()
+	if err != nil {
+		unavailable(c, "mongo", err)
+		return
+	}
+	var dump backupDump
+	if err := c.ShouldBindJSON(&dump); err != nil {
+		c.Error(apierr.BadRequest(err.Error()))
+		return
+	}
+	if !dump.Complete {
+		c.Error(apierr.Invalid("the backup is incomplete").With("backup_error", dump.Error))
+		return
+	}
+	if len(dump.Items) == 0 {
+		c.JSON(200, gin.H{"restored": 0})
+		return
+	}
+
+	models := make([]mongo.WriteModel, 0, len(dump.Items))
+	ids := make([]any, 0, len(dump.Items))
+	for i, raw := range dump.Items {
+		var doc bson.M
+		if err := bson.UnmarshalExtJSON(raw, false, &doc); err != nil {
+			c.Error(apierr.BadRequest(fmt.Sprintf("item %d: %v", i, err)))
+			return
+		}
+		id, ok := doc["_id"]
+		if !ok {
+			c.Error(apierr.Invalid(fmt.Sprintf("item %d: no _id", i)))
+			return
+		}
+		if name, ok := doc["name"].(string); ok {
+			clean, err := service.SanitizeName(name)
+			if err != nil {
+				c.Error(apierr.Invalid(fmt.Sprintf("item %d: %v", i, err)))
+				return
+			}
+			doc["name"] = clean
+		}
+		ids = append(ids, id)
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": id}).SetReplacement(doc).SetUpsert(true))
+	}
+
+	if err := a.injectedFault("mongo"); err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	res, err := col.BulkWrite(c.Request.Context(), models)
+	if err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	for _, id := range ids {
+		switch id := id.(type) {
+		case string:
+			a.cache.Invalidate("mongo", id)
+		case primitive.ObjectID:
+			a.cache.Invalidate("mongo", id.Hex())
+		}
+	}
+	c.JSON(200, gin.H{
+		"restored": len(dump.Items),
+		"inserted": res.UpsertedCount,
+		"replaced": res.MatchedCount,
+	})
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"multi-kind-app/internal/apierr"
+)
+
+// ──────────── Batch Handler ────────────
+
+const maxBatchSize = 20
+
+// maxDispatchDepth bounds how deeply dispatch may nest (a /batch holding a
+// /trace is two levels). It backs up routesTo: a sub-request that still
+// reaches a dispatching route fails instead of fanning out again.
+const maxDispatchDepth = 2
+
+type dispatchDepthKey struct{}
+
+// routesTo reports whether target, decoded and cleaned the way the router
+// will see it, is one of routes or below it. An unparseable target counts
+// as a match, so callers fail closed.
+func routesTo(target string, routes ...string) bool {
+	u, err := url.Parse(target)
+	if err != nil {
+		return true
+	}
+	p := path.Clean("/" + u.Path)
+	for _, r := range routes {
+		if p == r || strings.HasPrefix(p, r+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+type subRequest struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+type subResponse struct {
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// handleBatch — runs each sub-request through the same engine, in order, and
+// returns their responses as an array. One outer call, many inner operations.
+func handleBatch(engine *gin.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var reqs []subRequest
+		if err := c.ShouldBindJSON(&reqs); err != nil {
+			c.Error(apierr.BadRequest(err.Error()))
+			return
+		}
+		if len(reqs) == 0 || len(reqs) > maxBatchSize {
+			c.Error(apierr.BadRequest("batch must contain 1 to 20 requests"))
+			return
+		}
+
+		out := make([]subResponse, 0, len(reqs))
+		for _, sr := range reqs {
+			out = append(out, dispatch(c.Request.Context(), c.GetString("request_id"), engine, sr))
+		}
+		c.JSON(200, gin.H{"responses": out})
+	}
+}
+
+// dispatch serves sr through engine in-process and captures its response.
+func dispatch(ctx context.Context, reqID string, engine *gin.Engine, sr subRequest) subResponse {
+	method := strings.ToUpper(sr.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	if !strings.HasPrefix(sr.Path, "/") || routesTo(sr.Path, "/batch") {
+		return errorResponse(400, "invalid path: "+sr.Path)
+	}
+	depth, _ := ctx.Value(dispatchDepthKey{}).(int)
+	if depth >= maxDispatchDepth {
+		return errorResponse(400, "sub-requests nested too deeply: "+sr.Path)
+	}
+	ctx = context.WithValue(ctx, dispatchDepthKey{}, depth+1)
+
+	req, err := http.NewRequestWithContext(ctx, method, sr.Path, bytes.NewReader(sr.Body))
+	if err != nil {
+		return errorResponse(400, err.Error())
+	}
+	if len(sr.Body) > 0 {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set(requestIDHeader, reqID)
+
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+
+	body := rec.Body.Bytes()
+	if len(body) == 0 {
+		return subResponse{Status: rec.Code}
+	}
+	if !json.Valid(body) {
+		body, _ = json.Marshal(string(body))
+	}
+	return subResponse{Status: rec.Code, Body: body}
+}
+
+func errorResponse(status int, msg string) subResponse {
+	body, _ := json.Marshal(apierr.New(status, apierr.CodeBadRequest, msg).Problem(""))
+	return subResponse{Status: status, Body: body}
+}
@@ -0,0 +1,35 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"multi-kind-app/internal/apierr"
+	"multi-kind-app/internal/handlers/handlertest"
+)
+
+func TestBatchRejectsNestedBatch(t *testing.T) {
+	h := handlertest.New(t)
+	type batchBody struct {
+		Responses []struct {
+			Status int             `json:"status"`
+			Body   json.RawMessage `json:"body"`
+		} `json:"responses"`
+	}
+	for _, path := range []string{"/batch", "/%62atch", "/x/../batch", "/batch?x=1"} {
+		sub := []map[string]any{{"method": "POST", "path": path, "body": []map[string]string{{"path": "/healthz"}}}}
+		w := h.Do(http.MethodPost, "/batch", sub)
+		body := handlertest.Decode[batchBody](t, w)
+		if w.Code != http.StatusOK || len(body.Responses) != 1 || body.Responses[0].Status != http.StatusBadRequest {
+			t.Fatalf("%s: got %d %s", path, w.Code, w.Body)
+		}
+	}
+}
+
+func TestTraceRejectsSelfCalls(t *testing.T) {
+	h := handlertest.New(t)
+	for _, path := range []string{"/trace/trace/healthz", "/trace/%2574race/healthz", "/trace/%2562atch"} {
+		wantProblem(t, h.Do(http.MethodGet, path, nil), http.StatusBadRequest, apierr.CodeBadRequest)
+	}
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"multi-kind-app/internal/apierr"
+	"multi-kind-app/internal/storage/mongostore"
+	"multi-kind-app/internal/storage/redisstore"
+)
+
+// ──────────── Lazy Backend Clients ────────────
+
+func (a *App) getRedis() (*redis.Client, error) {
+	a.redisOnce.Do(a.initRedis)
+	a.clientsMu.RLock()
+	defer a.clientsMu.RUnlock()
+	if a.redisPrimary == nil && a.redisErr == nil {
+		return nil, errClientsClosed
+	}
+	return a.redisPrimary, a.redisErr
+}
+
+func (a *App) getRedisRead() (*redis.Client, error) {
+	a.redisOnce.Do(a.initRedis)
+	a.clientsMu.RLock()
+	defer a.clientsMu.RUnlock()
+	if a.redisRead == nil && a.redisErr == nil {
+		return nil, errClientsClosed
+	}
+	return a.redisRead, a.redisErr
+}
+
+func (a *App) getMongo() (*mongo.Database, error) {
+	a.mongoOnce.Do(a.initMongo)
+	a.clientsMu.RLock()
+	defer a.clientsMu.RUnlock()
+	if a.mongoDB == nil && a.mongoErr == nil {
+		return nil, errClientsClosed
+	}
+	return a.mongoDB, a.mongoErr
+}
+
+// getItemsCol is the items collection of getMongo's database.
+func (a *App) getItemsCol() (*mongo.Collection, error) {
+	a.mongoOnce.Do(a.initMongo)
+	a.clientsMu.RLock()
+	defer a.clientsMu.RUnlock()
+	if a.itemsCol == nil && a.mongoErr == nil {
+		return nil, errClientsClosed
+	}
+	return a.itemsCol, a.mongoErr
+}
+
+// unavailable is the response for a backend whose client failed to
+// initialise or that the dependency guard has seen down. err can carry a
+// connection string, so it is logged rather than sent.
+func unavailable(c *gin.Context, backend string, err error) {
+	slog.Warn("backend unavailable", "request_id", c.GetString("request_id"), "backend", backend, "err", err)
+	e := apierr.New(503, apierr.CodeUnavailable, backend+" unavailable").With("dependency", backend)
+	e.Err = err
+	c.Error(e)
+}
+
+func (a *App) initRedis() {
+	opts, err := redisstore.Options(a.cfg)
+	if err != nil {
+		a.redisErr = err
+		slog.Error("redis init failed", "err", err)
+		return
+	}
+	primary := a.newRedisClient(opts)
+	read := primary
+	if readAddr := a.cfg.RedisReadAddr; readAddr != "" {
+		read = a.newRedisClient(&redis.Options{Addr: readAddr, PoolSize: a.cfg.RedisPoolSize})
+	}
+	a.clientsMu.Lock()
+	a.redisPrimary, a.redisRead = primary, read
+	a.clientsMu.Unlock()
+	slog.Info("Redis client initialised", "addr", opts.Addr, "read_addr", a.cfg.RedisReadAddr)
+}
+
+func (a *App) initMongo() {
+	opts, err := mongostore.Options(a.cfg, mongoTraceMonitor)
+	if err != nil {
+		a.mongoErr = err
+		slog.Error("mongo init failed", "err", err)
+		return
+	}
+	opts.SetServerMonitor(a.mongoServerMonitor())
+	db, err := mongostore.Open(opts)
+	if err != nil {
+		a.mongoErr = err
+		slog.Error("mongo init failed", "err", err)
+		return
+	}
+	a.clientsMu.Lock()
+	a.mongoOpts, a.mongoDB, a.itemsCol = opts, db, db.Collection("items")
+	a.clientsMu.Unlock()
+	slog.Info("MongoDB client initialised")
+}
+
+// Close stops the App's background loops and releases the backend clients
+// in a fixed order (Redis, then Mongo, then idle outbound HTTP
+// connections), logging each step. Clients that were never initialised are
+// skipped. Call it after the server has drained; the first error is
+// returned but every client is still closed.
+func (a *App) Close(ctx context.Context) error {
+	a.stopOnce.Do(func() { close(a.stop) })
+
+	a.clientsMu.Lock()
+	primary, read, db := a.redisPrimary, a.redisRead, a.mongoDB
+	a.redisPrimary, a.redisRead, a.mongoDB, a.itemsCol = nil, nil, nil, nil
+	a.clientsMu.Unlock()
+
+	var first error
+	step := func(name string, fn func() error) {
+		if err := fn(); err != nil {
+			slog.Error("close failed", "client", name, "err", err)
+			if first == nil {
+				first = err
+			}
+			return
+		}
+		slog.Info("closed", "client", name)
+	}
+	if read != nil && read != primary {
+		step("redis-read", read.Close)
+	}
+	if primary != nil {
+		step("redis", primary.Close)
+	}
+	if db != nil {
+		step("mongo", func() error { return db.Client().Disconnect(ctx) })
+	}
+	step("http", func() error { a.httpClient.CloseIdleConnections(); return nil })
+	return first
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"multi-kind-app/internal/apierr"
+)
+
+// ──────────── Chained Outbound Calls ────────────
+
+// handleCompose — two sequential calls to the upstream where the second is
+// built from the first: it revalidates with the first response's ETag. The
+// outcome of the chain is then stored in Mongo. With outbound HTTP disabled
+// the calls are skipped and only the Mongo write happens.
+func (a *App) handleCompose(c *gin.Context) {
+	mdb, err := a.getMongo()
+	if err != nil {
+		unavailable(c, "mongo", err)
+		return
+	}
+	ctx := c.Request.Context()
+	reqID := c.GetString("request_id")
+
+	summary := bson.M{"request_id": reqID, "http_disabled": true, "at": time.Now().UTC()}
+	if !a.cfg.DisableOutboundHTTP {
+		var err error
+		if summary, err = a.composeChain(ctx, reqID); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+	if err := a.injectedFault("mongo"); err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	res, err := mdb.Collection("compose").InsertOne(ctx, summary)
+	if err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	delete(summary, "at")
+	summary["summary_id"] = res.InsertedID
+	c.JSON(200, summary)
+}
+
+// composeChain runs the two upstream calls and summarises them. On failure
+// it returns a 502 error instead.
+func (a *App) composeChain(ctx context.Context, reqID string) (bson.M, error) {
+	first, err := a.upstreamGet(ctx, map[string]string{requestIDHeader: reqID})
+	if err != nil {
+		return nil, apierr.Upstream(err, "first call")
+	}
+	etag := first.Header.Get("ETag")
+
+	hdr := map[string]string{requestIDHeader: reqID}
+	if etag != "" {
+		hdr["If-None-Match"] = etag
+	}
+	second, err := a.upstreamGet(ctx, hdr)
+	if err != nil {
+		return nil, apierr.Upstream(err, "second call").With("first_status", first.StatusCode)
+	}
+
+	return bson.M{
+		"request_id":    reqID,
+		"first_status":  first.StatusCode,
+		"etag":          etag,
+		"second_status": second.StatusCode,
+		"revalidated":   second.StatusCode == http.StatusNotModified,
+		"at":            time.Now().UTC(),
+	}, nil
+}
+
+// upstreamGet issues a GET to cfg.UpstreamURL and drains the body.
+func (a *App) upstreamGet(ctx context.Context, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.cfg.UpstreamURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return resp, nil
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+)
+
+// ──────────── Startup Connect ────────────
+
+// connectBaseDelay is the wait before the second connect attempt; it
+// doubles per attempt up to ReconnectInterval.
+const connectBaseDelay = 250 * time.Millisecond
+
+// Connect pings every enabled Connector backend in the background until it
+// answers. The first StartupAttempts retries back off
+// exponentially with jitter; after that a backend that is still down is
+// retried every ReconnectInterval, so one that comes up late is picked up
+// without a restart. Connected runs once the first ping succeeds.
+// Connect returns immediately; cancelling ctx stops the retries.
+func (a *App) Connect(ctx context.Context) {
+	for _, name := range a.backendNames {
+		if c, ok := a.backends[name].(Connector); ok && a.flags.Enabled(name) {
+			go a.connectLoop(ctx, name, c.Connected)
+		}
+	}
+}
+
+// connectLoop pings backend until it answers and onUp succeeds.
+// Each outcome feeds the dependency guard.
+func (a *App) connectLoop(ctx context.Context, backend string, onUp func(context.Context) error) {
+	maxDelay := time.Duration(a.cfg.ReconnectInterval)
+	for attempt := 1; ; attempt++ {
+		err := a.depPing(ctx, backend)
+		a.setDep(backend, err)
+		if err == nil {
+			err = onUp(ctx)
+		}
+		if err == nil {
+			slog.Info("backend connected", "backend", backend, "attempt", attempt)
+			return
+		}
+
+		// base * 2^(attempt-1) capped at the reconnect interval, then ±50%
+		// jitter so replicas restarted together don't retry in lockstep.
+		delay := maxDelay
+		if attempt < a.cfg.StartupAttempts && attempt < 32 {
+			delay = min(connectBaseDelay<<(attempt-1), maxDelay)
+		}
+		delay = delay/2 + rand.N(delay)
+		if attempt == a.cfg.StartupAttempts {
+			slog.Warn("backend still unavailable, retrying in background",
+				"backend", backend, "attempts", attempt, "err", err, "retry_in_ms", delay.Milliseconds())
+		} else {
+			slog.Debug("backend connect failed", "backend", backend, "attempt", attempt, "err", err, "retry_in_ms", delay.Milliseconds())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"multi-kind-app/internal/handlers/handlertest"
+)
+
+func TestConnectRetriesRedis(t *testing.T) {
+	h := handlertest.New(t)
+	h.Redis.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	h.App.Connect(ctx)
+
+	// The first failed ping marks Redis down, so the guard answers before
+	// the handler runs.
+	time.Sleep(100 * time.Millisecond)
+	w := h.Do(http.MethodGet, "/redis/a", nil)
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") == "" {
+		t.Fatalf("redis down: got %d %q %s, want a guarded 503", w.Code, w.Header().Get("Retry-After"), w.Body)
+	}
+
+	if err := h.Redis.Restart(); err != nil {
+		t.Fatal(err)
+	}
+	for deadline := time.Now().Add(1500 * time.Millisecond); ; time.Sleep(50 * time.Millisecond) {
+		w := h.Do(http.MethodGet, "/redis/a", nil)
+		if w.Code == http.StatusOK {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("redis back: still %d %s", w.Code, w.Body)
+		}
+	}
+}
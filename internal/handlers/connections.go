@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	"multi-kind-app/internal/apierr"
+	"multi-kind-app/internal/storage/mongostore"
+	"multi-kind-app/internal/storage/redisstore"
+)
+
+// ──────────── Connection Reset ────────────
+
+// resetDrain is how long a replaced client stays open so requests that
+// already hold it can finish.
+const resetDrain = 5 * time.Second
+
+// newRedisClient builds a Redis client with the standard hook chain.
+func (a *App) newRedisClient(opts *redis.Options) *redis.Client {
+	return redisstore.New(opts, time.Duration(a.cfg.RedisTimeout), traceHook{}, faultHook{a}, depHook{a})
+}
+
+// handleResetPool — rebuilds a backend's client and connection pool, for
+// when a backend restart has left stale connections behind. The old client
+// is closed after resetDrain rather than immediately.
+func (a *App) handleResetPool(c *gin.Context) {
+	backend := strings.ToLower(c.Param("backend"))
+	ctx := c.Request.Context()
+	a.resetMu.Lock()
+	defer a.resetMu.Unlock()
+
+	switch backend {
+	case "redis":
+		oldPrimary, err := a.getRedis()
+		if err != nil {
+			unavailable(c, backend, err)
+			return
+		}
+		oldRead, _ := a.getRedisRead()
+		primaryOpts := *oldPrimary.Options()
+		primary := a.newRedisClient(&primaryOpts)
+		read := primary
+		if oldRead != oldPrimary {
+			readOpts := *oldRead.Options()
+			read = a.newRedisClient(&readOpts)
+		}
+		a.clientsMu.Lock()
+		a.redisPrimary, a.redisRead = primary, read
+		a.clientsMu.Unlock()
+		time.AfterFunc(resetDrain, func() {
+			oldPrimary.Close()
+			if oldRead != oldPrimary {
+				oldRead.Close()
+			}
+		})
+		res := gin.H{"backend": backend, "redis_primary": primary.PoolStats(), "redis_read": read.PoolStats()}
+		if err := primary.Ping(ctx).Err(); err != nil {
+			res["ping_error"] = err.Error()
+		}
+		slog.Warn("connection pool reset", "backend", backend)
+		c.JSON(200, res)
+
+	case "mongo":
+		oldDB, err := a.getMongo()
+		if err != nil {
+			unavailable(c, backend, err)
+			return
+		}
+		db, err := mongostore.Open(a.mongoOpts)
+		if err != nil {
+			c.Error(apierr.Wrap(err, "mongo connect"))
+			return
+		}
+		client := db.Client()
+		a.clientsMu.Lock()
+		a.mongoDB, a.itemsCol = db, db.Collection("items")
+		a.clientsMu.Unlock()
+		old := oldDB.Client()
+		time.AfterFunc(resetDrain, func() { old.Disconnect(context.Background()) })
+		res := gin.H{"backend": backend, "open_sessions": client.NumberSessionsInProgress()}
+		if err := a.backendPings()["mongo"](ctx); err != nil {
+			res["ping_error"] = err.Error()
+		}
+		slog.Warn("connection pool reset", "backend", backend)
+		c.JSON(200, res)
+
+	case "http":
+		a.httpClient.CloseIdleConnections()
+		slog.Warn("connection pool reset", "backend", backend)
+		c.JSON(200, gin.H{"backend": backend, "idle_closed": true})
+
+	default:
+		c.Error(apierr.NotFound("unknown backend: " + backend))
+	}
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ──────────── Content Hash ────────────
+
+const contentHashHeader = "X-Content-Hash"
+
+// hashWriter holds back a JSON response so its SHA-256 can go in a header.
+// Anything that isn't JSON, grows past max, or is flushed early switches to
+// passthrough and is sent unhashed.
+type hashWriter struct {
+	gin.ResponseWriter
+	buf         bytes.Buffer
+	max         int
+	passthrough bool
+}
+
+func (w *hashWriter) Write(b []byte) (int, error) {
+	if !w.passthrough && (!isJSON(w.Header().Get("Content-Type")) || w.buf.Len()+len(b) > w.max) {
+		w.release()
+	}
+	if w.passthrough {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.buf.Write(b)
+}
+
+func (w *hashWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *hashWriter) Flush() {
+	w.release()
+	w.ResponseWriter.Flush()
+}
+
+// release sends whatever is buffered and stops buffering.
+func (w *hashWriter) release() {
+	if w.passthrough {
+		return
+	}
+	w.passthrough = true
+	if w.buf.Len() > 0 {
+		w.ResponseWriter.Write(w.buf.Bytes())
+		w.buf.Reset()
+	}
+}
+
+func isJSON(contentType string) bool {
+	return strings.HasPrefix(contentType, "application/json") ||
+		strings.HasPrefix(contentType, "application/problem+json")
+}
+
+// contentHash sets X-Content-Hash on JSON responses up to maxBytes, so
+// replay tooling can compare responses without diffing bodies.
+func contentHash(maxBytes int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hw := &hashWriter{ResponseWriter: c.Writer, max: maxBytes}
+		c.Writer = hw
+		// Put the real writer back even if a handler panics, so the problem
+		// body recovery writes goes out instead of into a buffer nobody
+		// flushes. Whatever the handler had buffered is dropped.
+		defer func() { c.Writer = hw.ResponseWriter }()
+		c.Next()
+		if hw.passthrough {
+			return
+		}
+		if hw.buf.Len() == 0 {
+			hw.ResponseWriter.WriteHeaderNow()
+			return
+		}
+		sum := sha256.Sum256(hw.buf.Bytes())
+		hw.Header().Set(contentHashHeader, "sha256="+hex.EncodeToString(sum[:]))
+		hw.release()
+	}
+}
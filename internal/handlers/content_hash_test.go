@@ -0,0 +1,24 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"multi-kind-app/internal/apierr"
+	"multi-kind-app/internal/config"
+	"multi-kind-app/internal/handlers/handlertest"
+)
+
+func TestContentHashPanic(t *testing.T) {
+	h := handlertest.New(t, func(c *config.Config) { c.ContentHash = true })
+	h.Router.GET("/boom", func(*gin.Context) { panic("boom") })
+
+	wantProblem(t, h.Do(http.MethodGet, "/boom", nil), http.StatusInternalServerError, apierr.CodeInternal)
+
+	w := h.Do(http.MethodGet, "/healthz", nil)
+	if w.Header().Get("X-Content-Hash") == "" {
+		t.Fatalf("no content hash on a normal response: %v", w.Header())
+	}
+}
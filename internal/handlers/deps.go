@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// ──────────── Dependency Guard ────────────
+
+// depRecheck is how long a dependency seen down is trusted to stay down.
+// After that the next guarded request pings it again, so the app heals
+// without waiting for the background reconnect loop.
+const depRecheck = 2 * time.Second
+
+// errClientsClosed is returned by the client accessors after App.Close.
+var errClientsClosed = errors.New("client closed")
+
+// depState is what the app last saw of one dependency. Unknown counts as
+// up: the guard only blocks a backend something has actually seen fail.
+type depState struct {
+	down atomic.Bool
+
+	mu      sync.Mutex
+	lastErr string
+	checked time.Time
+}
+
+// setDep records the outcome of a round trip to name. A nil err marks it up.
+func (a *App) setDep(name string, err error) {
+	d, ok := a.deps[name]
+	if !ok || (err == nil && !d.down.Load()) {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.checked = time.Now()
+	if err != nil {
+		d.lastErr = err.Error()
+	}
+	d.down.Store(err != nil)
+}
+
+// depDown reports whether name is unavailable, pinging it first when the
+// last failure is older than depRecheck.
+func (a *App) depDown(ctx context.Context, name string) (string, bool) {
+	d, ok := a.deps[name]
+	if !ok || !d.down.Load() {
+		return "", false
+	}
+	d.mu.Lock()
+	stale := time.Since(d.checked) >= depRecheck
+	if stale {
+		// Claim the recheck so concurrent requests don't all ping.
+		d.checked = time.Now()
+	}
+	d.mu.Unlock()
+	if stale {
+		a.setDep(name, a.depPing(ctx, name))
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.lastErr, d.down.Load()
+}
+
+// depPing runs the backend's Ping under pingTimeout.
+func (a *App) depPing(ctx context.Context, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	return a.backends[name].Ping(ctx)
+}
+
+// guard writes a 503 naming the first unavailable dependency and reports
+// whether the request may proceed.
+func (a *App) guard(c *gin.Context, deps ...string) bool {
+	for _, name := range deps {
+		if !a.flags.Enabled(name) {
+			unavailable(c, name, errDisabled)
+			c.Abort()
+			return false
+		}
+		if lastErr, down := a.depDown(c.Request.Context(), name); down {
+			c.Header("Retry-After", strconv.Itoa(int(depRecheck.Seconds())))
+			unavailable(c, name, errors.New(lastErr))
+			c.Abort()
+			return false
+		}
+	}
+	return true
+}
+
+// requires guards a route on deps. With FALLBACK_MEMORY on, routes that
+// have an in-memory path should list mongo via itemDeps instead.
+func (a *App) requires(deps ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if a.guard(c, deps...) {
+			c.Next()
+		}
+	}
+}
+
+// itemDeps are the dependencies of the item routes that fall back to
+// memory: Mongo is optional while the fallback is enabled.
+func (a *App) itemDeps() []string {
+	if a.cfg.FallbackMemory {
+		return []string{"redis"}
+	}
+	return []string{"redis", "mongo"}
+}
+
+// depHook tracks Redis availability from the commands the app already
+// sends: network errors mark it down, any reply marks it up.
+type depHook struct{ app *App }
+
+func (depHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h depHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		err := next(ctx, cmd)
+		h.observe(err)
+		return err
+	}
+}
+
+func (h depHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		err := next(ctx, cmds)
+		h.observe(err)
+		return err
+	}
+}
+
+func (h depHook) observe(err error) {
+	var ne net.Error
+	switch {
+	case errors.As(err, &ne):
+		h.app.setDep("redis", err)
+	case err == nil || errors.Is(err, redis.Nil):
+		h.app.setDep("redis", nil)
+	}
+}
+
+// mongoServerMonitor tracks Mongo availability from the driver's own
+// heartbeats, so no extra commands are sent to find out.
+func (a *App) mongoServerMonitor() *event.ServerMonitor {
+	return &event.ServerMonitor{
+		ServerHeartbeatSucceeded: func(*event.ServerHeartbeatSucceededEvent) { a.setDep("mongo", nil) },
+		ServerHeartbeatFailed:    func(e *event.ServerHeartbeatFailedEvent) { a.setDep("mongo", e.Failure) },
+	}
+}
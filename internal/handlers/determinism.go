@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"multi-kind-app/internal/apierr"
+)
+
+// ──────────── Determinism Probe ────────────
+
+type probe struct {
+	name string
+	read func(ctx context.Context) ([]byte, error)
+}
+
+// determinismProbes are fixed, read-only operations — one per backend.
+func (a *App) determinismProbes() []probe {
+	return []probe{
+		{"redis", func(ctx context.Context) ([]byte, error) {
+			rdbRead, err := a.getRedisRead()
+			if err != nil {
+				return nil, err
+			}
+			v, err := rdbRead.Get(ctx, "determinism:probe").Bytes()
+			if errors.Is(err, redis.Nil) {
+				return nil, nil
+			}
+			return v, err
+		}},
+		{"mongo", func(ctx context.Context) ([]byte, error) {
+			col, err := a.getItemsCol()
+			if err != nil {
+				return nil, err
+			}
+			if err := a.injectedFault("mongo"); err != nil {
+				return nil, err
+			}
+			var doc bson.M
+			opts := options.FindOne().SetSort(bson.D{{Key: "_id", Value: 1}})
+			err = col.FindOne(ctx, bson.M{}, opts).Decode(&doc)
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return nil, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(doc)
+		}},
+		{"http", func(ctx context.Context) ([]byte, error) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.cfg.UpstreamURL, nil)
+			if err != nil {
+				return nil, err
+			}
+			resp, err := a.httpClient.Do(req)
+			if err != nil {
+				return nil, err
+			}
+			defer resp.Body.Close()
+			return io.ReadAll(resp.Body)
+		}},
+	}
+}
+
+// handleDeterminismProbe — runs each probe twice and compares sha256 digests,
+// pointing out backends whose output would not survive record/replay as-is.
+func (a *App) handleDeterminismProbe(c *gin.Context) {
+	ctx := c.Request.Context()
+	results := gin.H{}
+	mismatched := []string{}
+
+	for _, p := range a.determinismProbes() {
+		if !a.flags.Enabled(p.name) {
+			continue
+		}
+		first, err1 := p.read(ctx)
+		second, err2 := p.read(ctx)
+		if err := errors.Join(err1, err2); err != nil {
+			slog.Warn("determinism probe failed", "request_id", c.GetString("request_id"), "backend", p.name, "err", err)
+			results[p.name] = gin.H{"error": apierr.Wrap(err, p.name).Detail}
+			continue
+		}
+		h1, h2 := sha256Hex(first), sha256Hex(second)
+		if h1 != h2 {
+			mismatched = append(mismatched, p.name)
+		}
+		results[p.name] = gin.H{"run1": h1, "run2": h2, "match": h1 == h2}
+	}
+	c.JSON(200, gin.H{"backends": results, "mismatched": mismatched, "deterministic": len(mismatched) == 0})
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"multi-kind-app/internal/apierr"
+	"multi-kind-app/internal/service"
+)
+
+// ──────────── Cross-Backend Diff ────────────
+
+type diffRecord struct {
+	Name      string
+	Value     string
+	WrittenAt time.Time
+}
+
+// diffRoundTrips write a record to one backend and read back whatever that
+// backend actually stored, in its native representation.
+func (a *App) diffRoundTrips() map[string]func(ctx context.Context, rec diffRecord) (map[string]any, error) {
+	return onlyEnabled(a.flags, map[string]func(ctx context.Context, rec diffRecord) (map[string]any, error){
+		"redis": func(ctx context.Context, rec diffRecord) (map[string]any, error) {
+			rdb, err := a.getRedis()
+			if err != nil {
+				return nil, err
+			}
+			key := "diff:" + rec.Name
+			if err := rdb.HSet(ctx, key, "name", rec.Name, "value", rec.Value, "written_at", rec.WrittenAt).Err(); err != nil {
+				return nil, err
+			}
+			rdb.Expire(ctx, key, 10*time.Minute)
+			h, err := rdb.HGetAll(ctx, key).Result()
+			if err != nil {
+				return nil, err
+			}
+			out := make(map[string]any, len(h))
+			for k, v := range h {
+				out[k] = v
+			}
+			return out, nil
+		},
+		"mongo": func(ctx context.Context, rec diffRecord) (map[string]any, error) {
+			mdb, err := a.getMongo()
+			if err != nil {
+				return nil, err
+			}
+			if err := a.injectedFault("mongo"); err != nil {
+				return nil, err
+			}
+			c := mdb.Collection("diff")
+			filter := bson.M{"name": rec.Name}
+			update := bson.M{"$set": bson.M{"name": rec.Name, "value": rec.Value, "written_at": rec.WrittenAt}}
+			if _, err := c.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true)); err != nil {
+				return nil, err
+			}
+			var doc bson.M
+			if err := c.FindOne(ctx, filter).Decode(&doc); err != nil {
+				return nil, err
+			}
+			return doc, nil
+		},
+	})
+}
+
+type fieldDiff struct {
+	A     any    `json:"a"`
+	B     any    `json:"b"`
+	AType string `json:"a_type"`
+	BType string `json:"b_type"`
+	Equal bool   `json:"equal"`
+}
+
+// handleDiff — writes the same record to two backends and compares what
+// each one hands back, field by field.
+func (a *App) handleDiff(c *gin.Context) {
+	var req struct {
+		A     string `json:"a"`
+		B     string `json:"b"`
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apierr.BadRequest(err.Error()))
+		return
+	}
+	if req.A == "" {
+		req.A = "redis"
+	}
+	if req.B == "" {
+		req.B = "mongo"
+	}
+	writeA, okA := a.diffRoundTrips()[req.A]
+	writeB, okB := a.diffRoundTrips()[req.B]
+	if !okA || !okB || req.A == req.B {
+		c.Error(apierr.BadRequest("a and b must be two different backends: redis, mongo"))
+		return
+	}
+	name, err := service.SanitizeName(req.Name)
+	if err != nil || name == "" {
+		c.Error(apierr.Invalid("invalid name"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	rec := diffRecord{Name: name, Value: req.Value, WrittenAt: time.Now().UTC()}
+	docA, err := writeA(ctx, rec)
+	if err != nil {
+		c.Error(apierr.Wrap(err, req.A))
+		return
+	}
+	docB, err := writeB(ctx, rec)
+	if err != nil {
+		c.Error(apierr.Wrap(err, req.B))
+		return
+	}
+
+	fields := map[string]fieldDiff{}
+	differing := []string{}
+	for _, k := range unionKeys(docA, docB) {
+		av, bv := docA[k], docB[k]
+		d := fieldDiff{A: av, B: bv, AType: fmt.Sprintf("%T", av), BType: fmt.Sprintf("%T", bv)}
+		d.Equal = d.AType == d.BType && fmt.Sprint(av) == fmt.Sprint(bv)
+		if !d.Equal {
+			differing = append(differing, k)
+		}
+		fields[k] = d
+	}
+	c.JSON(200, gin.H{"a": req.A, "b": req.B, "fields": fields, "differing": differing})
+}
+
+func unionKeys(a, b map[string]any) []string {
+	seen := map[string]bool{}
+	for k := range a {
+		seen[k] = true
+	}
+	for k := range b {
+		seen[k] = true
+	}
+	keys := make([]string, 0, len(seen))
+	for k := range seen {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"errors"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+)
+
+// ──────────── In-Memory Fallback ────────────
+
+// useFallback reports whether err means Mongo is unreachable and the
+// in-memory store should serve the request instead.
+func (a *App) useFallback(err error) bool {
+	if !a.cfg.FallbackMemory || err == nil {
+		return false
+	}
+	var sse topology.ServerSelectionError
+	return errors.As(err, &sse) || mongo.IsNetworkError(err) || mongo.IsTimeout(err)
+}
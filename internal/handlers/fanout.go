@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"multi-kind-app/internal/apierr"
+	"multi-kind-app/internal/service"
+	"multi-kind-app/internal/storage"
+)
+
+// ──────────── Fan-Out Write ────────────
+
+// fanoutResult is one store's outcome of POST /fanout.
+type fanoutResult struct {
+	OK        bool        `json:"ok"`
+	Skipped   bool        `json:"skipped,omitempty"`
+	Status    int         `json:"status"`
+	Code      apierr.Code `json:"code,omitempty"`
+	Error     string      `json:"error,omitempty"`
+	LatencyMs float64     `json:"latency_ms"`
+}
+
+// handleFanout — creates the same item, under one id, in every enabled
+// store concurrently, each under its backend's own timeout. A failing
+// store neither fails nor hides the others: each reports its status,
+// error and latency, and the response is 200 with the counts. A store
+// switched off by its feature flag is reported as skipped, not written.
+func (a *App) handleFanout(c *gin.Context) {
+	var item Item
+	if err := c.ShouldBindJSON(&item); err != nil {
+		c.Error(apierr.BadRequest(err.Error()))
+		return
+	}
+	var err error
+	if item.Name, err = service.SanitizeName(item.Name); err != nil {
+		c.Error(err)
+		return
+	}
+	if item.ID == "" {
+		item.ID = a.items.NewID()
+	}
+	timeouts := map[string]time.Duration{
+		"mongo": time.Duration(a.cfg.MongoTimeout),
+		"redis": time.Duration(a.cfg.RedisTimeout),
+	}
+
+	stores := a.items.Stores()
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		out = make(map[string]fanoutResult, len(stores))
+	)
+	for _, store := range stores {
+		if !a.flags.Enabled(store) {
+			out[store] = fanoutResult{Skipped: true, Status: 503, Code: apierr.CodeUnavailable, Error: store + " unavailable: " + errDisabled.Error()}
+			continue
+		}
+		wg.Add(1)
+		go func(store string) {
+			defer wg.Done()
+			timeout, ok := timeouts[store]
+			if !ok {
+				timeout = time.Duration(a.cfg.RequestTimeout)
+			}
+			ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+			defer cancel()
+
+			start := time.Now()
+			var err error
+			if lastErr, down := a.depDown(ctx, store); down {
+				err = fmt.Errorf("%w: %s", storage.ErrUnavailable, lastErr)
+			} else {
+				_, err = a.items.Create(ctx, store, item)
+			}
+			res := fanoutResult{OK: true, Status: 201, LatencyMs: float64(time.Since(start).Microseconds()) / 1000}
+			if err != nil {
+				e := apierr.From(err)
+				res.OK, res.Status, res.Code, res.Error = false, e.Status, e.Code, e.Detail
+			}
+			mu.Lock()
+			out[store] = res
+			mu.Unlock()
+		}(store)
+	}
+	wg.Wait()
+
+	created, skipped := 0, 0
+	for _, res := range out {
+		switch {
+		case res.OK:
+			created++
+		case res.Skipped:
+			skipped++
+		}
+	}
+	c.JSON(200, gin.H{"id": item.ID, "created": created, "failed": len(out) - created - skipped, "skipped": skipped, "stores": out})
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	"multi-kind-app/internal/apierr"
+)
+
+// ──────────── Fault Injection ────────────
+
+// injectedFault consumes one pending failure for backend, if any, and
+// returns the synthetic error to surface in its place.
+func (a *App) injectedFault(backend string) error {
+	n := a.injected[backend]
+	for {
+		cur := n.Load()
+		if cur <= 0 {
+			return nil
+		}
+		if n.CompareAndSwap(cur, cur-1) {
+			return fmt.Errorf("injected %s failure", backend)
+		}
+	}
+}
+
+func (a *App) injectedCounts() map[string]int64 {
+	out := make(map[string]int64, len(a.injected))
+	for name, n := range a.injected {
+		out[name] = n.Load()
+	}
+	return out
+}
+
+// faultHook fails Redis commands and pipelines while failures are pending.
+type faultHook struct{ app *App }
+
+func (faultHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (h faultHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if err := h.app.injectedFault("redis"); err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+		return next(ctx, cmd)
+	}
+}
+
+func (h faultHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if err := h.app.injectedFault("redis"); err != nil {
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+			return err
+		}
+		return next(ctx, cmds)
+	}
+}
+
+// faultTransport fails outbound HTTP requests while failures are pending.
+type faultTransport struct {
+	app  *App
+	base http.RoundTripper
+}
+
+func (t faultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.app.injectedFault("http"); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+// handleInject — arms the next fail_next operations on :backend to fail.
+func (a *App) handleInject(c *gin.Context) {
+	n, ok := a.injected[c.Param("backend")]
+	if !ok {
+		c.Error(apierr.NotFound("unknown backend: " + c.Param("backend")))
+		return
+	}
+	var req struct {
+		FailNext int64 `json:"fail_next"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.FailNext < 0 {
+		c.Error(apierr.BadRequest("body must be {\"fail_next\": n} with n >= 0"))
+		return
+	}
+	n.Store(req.FailNext)
+	c.JSON(200, gin.H{"backend": c.Param("backend"), "fail_next": req.FailNext})
+}
+
+// ──────────── Admin Auth ────────────
+
+// requireAPIKey guards admin routes with the X-API-Key header. With no
+// ADMIN_API_KEY configured the admin API is disabled outright.
+func (a *App) requireAPIKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := a.cfg.AdminAPIKey
+		if key == "" {
+			apierr.Abort(c, apierr.New(403, apierr.CodeForbidden, "admin API disabled: ADMIN_API_KEY not set"))
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader("X-API-Key")), []byte(key)) != 1 {
+			apierr.Abort(c, apierr.New(401, apierr.CodeUnauthorized, "invalid API key"))
+			return
+		}
+		c.Next()
+	}
+}
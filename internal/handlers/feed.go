@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"multi-kind-app/internal/apierr"
+)
+
+// ──────────── Keyset Feed ────────────
+
+// feedFilter matches the ids after the cursor in _id order. Collections
+// hold both string and ObjectID ids and BSON sorts every string before
+// every ObjectID, but $gt only compares within one type, so a string
+// cursor must also let all ObjectIDs through. The cursor is parsed like
+// docIDParam: 24 hex digits are an ObjectID.
+func feedFilter(after string) bson.M {
+	if after == "" {
+		return bson.M{}
+	}
+	if oid, err := primitive.ObjectIDFromHex(after); err == nil {
+		return bson.M{"_id": bson.M{"$gt": oid}}
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{"_id": bson.M{"$gt": after}},
+		bson.M{"_id": bson.M{"$type": "objectId"}},
+	}}
+}
+
+// handleFeed — keyset pagination over item ids: each page starts strictly
+// after ?after_id= (empty = from the beginning) so paging cost doesn't grow
+// with depth the way ?offset= does. next_cursor is omitted on the last page.
+func (a *App) handleFeed(c *gin.Context) {
+	col, err := a.getItemsCol()
+	if err != nil {
+		unavailable(c, "mongo", err)
+		return
+	}
+	after := c.Query("after_id")
+	if !utf8.ValidString(after) || len(after) > 1024 {
+		c.Error(apierr.BadRequest("invalid after_id"))
+		return
+	}
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", "20"), 10, 64)
+	if err != nil || limit <= 0 {
+		c.Error(apierr.BadRequest("invalid limit"))
+		return
+	}
+	limit = min(limit, a.cfg.MaxPageSize)
+	ctx := c.Request.Context()
+
+	if err := a.injectedFault("mongo"); err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(limit + 1)
+	cur, err := col.Find(ctx, liveFilter(feedFilter(after)), opts)
+	if err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	items := []Item{}
+	if err := cur.All(ctx, &items); err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+
+	body := gin.H{"after_id": after, "limit": limit}
+	if int64(len(items)) > limit {
+		items = items[:limit]
+		body["next_cursor"] = items[limit-1].ID
+	}
+	body["items"] = items
+	c.JSON(200, body)
+}
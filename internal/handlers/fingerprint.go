@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ──────────── State Fingerprint ────────────
+
+// fingerprintCounts are the cheap counts that summarise app state after a run.
+func (a *App) fingerprintCounts() map[string]func(ctx context.Context) (int64, error) {
+	return onlyEnabled(a.flags, map[string]func(ctx context.Context) (int64, error){
+		"mongo.items": func(ctx context.Context) (int64, error) {
+			col, err := a.getItemsCol()
+			if err != nil {
+				return 0, err
+			}
+			if err := a.injectedFault("mongo"); err != nil {
+				return 0, err
+			}
+			return col.CountDocuments(ctx, bson.M{})
+		},
+		"redis.item": func(ctx context.Context) (int64, error) {
+			return a.countKeys(ctx, "item:*")
+		},
+		"redis.session": func(ctx context.Context) (int64, error) {
+			return a.countKeys(ctx, sessionPrefix+"*")
+		},
+	})
+}
+
+// countKeys counts keys matching pattern with SCAN, never KEYS.
+func (a *App) countKeys(ctx context.Context, pattern string) (int64, error) {
+	rdbRead, err := a.getRedisRead()
+	if err != nil {
+		return 0, err
+	}
+	var (
+		n      int64
+		cursor uint64
+	)
+	for {
+		keys, next, err := rdbRead.Scan(ctx, cursor, pattern, 500).Result()
+		if err != nil {
+			return n, err
+		}
+		n += int64(len(keys))
+		if cursor = next; cursor == 0 {
+			return n, nil
+		}
+	}
+}
+
+// handleFingerprint — counts per backend plus a hash over all of them, so a
+// replay's side effects can be compared against the recording in one call.
+func (a *App) handleFingerprint(c *gin.Context) {
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		counts = map[string]int64{}
+		errs   = map[string]string{}
+	)
+	for name, count := range a.fingerprintCounts() {
+		wg.Add(1)
+		go func(name string, count func(context.Context) (int64, error)) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(c.Request.Context(), pingTimeout)
+			defer cancel()
+			n, err := count(ctx)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[name] = err.Error()
+				return
+			}
+			counts[name] = n
+		}(name, count)
+	}
+	wg.Wait()
+
+	// encoding/json sorts map keys, so the hash input is canonical.
+	canonical, _ := json.Marshal(counts)
+	body := gin.H{"counts": counts, "hash": sha256Hex(canonical)}
+	if len(errs) > 0 {
+		body["errors"] = errs
+	}
+	c.JSON(200, body)
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"multi-kind-app/internal/apierr"
+	"multi-kind-app/internal/features"
+)
+
+// ──────────── Feature Flags ────────────
+
+// errDisabled is the guard's reason for a backend switched off at runtime.
+var errDisabled = errors.New("disabled by feature flag")
+
+// onlyEnabled drops the entries of a per-backend map whose backend is off.
+// Keys may be qualified ("mongo.items"); the part before the dot is the
+// backend.
+func onlyEnabled[V any](f *features.Flags, m map[string]V) map[string]V {
+	for key := range m {
+		backend, _, _ := strings.Cut(key, ".")
+		if !f.Enabled(backend) {
+			delete(m, key)
+		}
+	}
+	return m
+}
+
+// handleFlags — every backend's current flag.
+func (a *App) handleFlags(c *gin.Context) {
+	c.JSON(200, gin.H{"backends": a.flags.Snapshot()})
+}
+
+// handleSetFlag — switches :backend on or off. Its routes stay registered
+// and answer 503 while off; a backend that was off at startup has no
+// routes, so enabling it is a 409 until the next restart.
+func (a *App) handleSetFlag(c *gin.Context) {
+	var req struct {
+		Enabled *bool `json:"enabled"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.Enabled == nil {
+		c.Error(apierr.BadRequest("body must be {\"enabled\": true|false}"))
+		return
+	}
+	name := strings.ToLower(c.Param("backend"))
+	switch err := a.flags.Set(name, *req.Enabled); {
+	case errors.Is(err, features.ErrUnknown):
+		c.Error(apierr.NotFound("unknown backend: " + name))
+	case errors.Is(err, features.ErrRestartRequired):
+		c.Error(apierr.Conflict(err.Error()))
+	default:
+		c.JSON(200, gin.H{"backend": name, "enabled": *req.Enabled})
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"multi-kind-app/internal/apierr"
+)
+
+// ──────────── GridFS ────────────
+
+// maxUploadSize caps a single GridFS upload.
+const maxUploadSize = 32 << 20
+
+// gridfsBucket opens the default "fs" bucket. Buckets are cheap, and the
+// GridFS API takes deadlines per bucket rather than per call, so each
+// request gets its own bucket carrying the request's deadline.
+func (a *App) gridfsBucket(c *gin.Context) (*gridfs.Bucket, error) {
+	mdb, err := a.getMongo()
+	if err != nil {
+		return nil, err
+	}
+	b, err := gridfs.NewBucket(mdb)
+	if err != nil {
+		return nil, err
+	}
+	if dl, ok := c.Request.Context().Deadline(); ok {
+		b.SetWriteDeadline(dl)
+		b.SetReadDeadline(dl)
+	}
+	return b, nil
+}
+
+// handleGridFSUpload — stores the multipart "file" field in GridFS and
+// returns its id. The original content type is kept in the file metadata.
+func (a *App) handleGridFSUpload(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadSize)
+	fh, err := c.FormFile("file")
+	if err != nil {
+		c.Error(apierr.BadRequest("multipart field \"file\" required: " + err.Error()))
+		return
+	}
+	f, err := fh.Open()
+	if err != nil {
+		c.Error(apierr.BadRequest(err.Error()))
+		return
+	}
+	defer f.Close()
+
+	if err := a.injectedFault("mongo"); err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	bucket, err := a.gridfsBucket(c)
+	if err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	opts := options.GridFSUpload().SetMetadata(bson.M{"content_type": fh.Header.Get("Content-Type")})
+	id, err := bucket.UploadFromStream(fh.Filename, f, opts)
+	if err != nil {
+		c.Error(apierr.Wrap(err, "mongo gridfs"))
+		return
+	}
+	c.JSON(201, gin.H{"id": id.Hex(), "filename": fh.Filename, "size": fh.Size})
+}
+
+// handleGridFSDownload — streams a GridFS file back by id.
+func (a *App) handleGridFSDownload(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.Error(apierr.BadRequest("id must be a 24-char hex ObjectID"))
+		return
+	}
+	if err := a.injectedFault("mongo"); err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	bucket, err := a.gridfsBucket(c)
+	if err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	stream, err := bucket.OpenDownloadStream(id)
+	if errors.Is(err, gridfs.ErrFileNotFound) {
+		c.Error(apierr.NotFound("file not found"))
+		return
+	}
+	if err != nil {
+		c.Error(apierr.Wrap(err, "mongo gridfs"))
+		return
+	}
+	defer stream.Close()
+
+	file := stream.GetFile()
+	contentType := "application/octet-stream"
+	var meta struct {
+		ContentType string `bson:"content_type"`
+	}
+	if file.Metadata != nil && bson.Unmarshal(file.Metadata, &meta) == nil && meta.ContentType != "" {
+		contentType = meta.ContentType
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Length", strconv.FormatInt(file.Length, 10))
+	c.Header("Content-Disposition", "attachment; filename="+strconv.Quote(file.Name))
+	c.Status(200)
+	if _, err := io.Copy(c.Writer, stream); err != nil {
+		// Headers are already sent; all that's left is to log.
+		reqLog(c).Error("gridfs download interrupted", "id", id.Hex(), "err", err)
+	}
+}
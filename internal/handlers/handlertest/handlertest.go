@@ -0,0 +1,164 @@
+// Package handlertest boots the app's router against in-memory fakes, so
+// handler tests run without a Redis or Mongo server or the network.
+package handlertest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+
+	"multi-kind-app/internal/config"
+	"multi-kind-app/internal/handlers"
+	"multi-kind-app/internal/storage/memstore"
+)
+
+// UpstreamURL is the outbound HTTP target the harness configures; stub it
+// with Harness.HTTP.Set.
+const UpstreamURL = "http://upstream.test/todos/1"
+
+// Harness is a router wired to fakes:
+//   - every /stores/:store/items store is a memstore.ItemRepo (Repos);
+//   - /users lives in a memstore.UserRepo (Users);
+//   - outbound HTTP goes to a StubTransport (HTTP);
+//   - Redis is an in-process miniredis (Redis).
+//
+// Mongo points at a closed port, so a route that still needs a real client
+// fails fast instead of reaching anything.
+type Harness struct {
+	App    *handlers.App
+	Router *gin.Engine
+	Repos  map[string]*memstore.ItemRepo
+	Users  *memstore.UserRepo
+	HTTP   *StubTransport
+	Redis  *miniredis.Miniredis
+}
+
+// New builds a Harness. configure, if given, adjusts the config before the
+// app is built.
+func New(tb testing.TB, configure ...func(*config.Config)) *Harness {
+	tb.Helper()
+	gin.SetMode(gin.TestMode)
+
+	mr := miniredis.RunT(tb)
+	cfg := config.Default()
+	cfg.RedisAddr = mr.Addr()
+	// config.Load fills per-backend timeouts in from the request timeout;
+	// Default leaves them at zero, which would expire every command.
+	cfg.RedisTimeout = cfg.RequestTimeout
+	cfg.MongoURI = "mongodb://127.0.0.1:1"
+	cfg.UpstreamURL = UpstreamURL
+	cfg.HTTPRetries = 1
+	for _, fn := range configure {
+		fn(cfg)
+	}
+
+	h := &Harness{
+		Repos: map[string]*memstore.ItemRepo{"mongo": memstore.NewItemRepo(), "redis": memstore.NewItemRepo()},
+		Users: memstore.NewUserRepo(),
+		HTTP:  &StubTransport{},
+		Redis: mr,
+	}
+	opts := []handlers.Option{handlers.WithHTTPTransport(h.HTTP), handlers.WithUserRepo(h.Users)}
+	for name, repo := range h.Repos {
+		opts = append(opts, handlers.WithItemRepo(name, repo))
+	}
+	h.App = handlers.NewApp(cfg, opts...)
+	tb.Cleanup(func() { h.App.Close(context.Background()) })
+	h.Router = h.App.Router()
+	return h
+}
+
+// Do serves one request. A non-nil body that isn't a string or []byte is
+// sent as JSON.
+func (h *Harness) Do(method, path string, body any) *httptest.ResponseRecorder {
+	var r io.Reader
+	switch b := body.(type) {
+	case nil:
+	case string:
+		r = strings.NewReader(b)
+	case []byte:
+		r = bytes.NewReader(b)
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			panic(err)
+		}
+		r = bytes.NewReader(data)
+	}
+	req := httptest.NewRequest(method, path, r)
+	if r != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	h.Router.ServeHTTP(w, req)
+	return w
+}
+
+// Decode unmarshals a response body into a fresh T, failing the test on
+// malformed JSON.
+func Decode[T any](tb testing.TB, w *httptest.ResponseRecorder) T {
+	tb.Helper()
+	var v T
+	if err := json.Unmarshal(w.Body.Bytes(), &v); err != nil {
+		tb.Fatalf("decode %q: %v", w.Body.String(), err)
+	}
+	return v
+}
+
+// StubTransport answers outbound requests from canned responses keyed by
+// "METHOD URL" and records every request it sees. Unstubbed requests fail
+// like a network error would.
+type StubTransport struct {
+	mu        sync.Mutex
+	responses map[string]stubResponse
+	requests  []string
+}
+
+type stubResponse struct {
+	status int
+	body   string
+}
+
+// Set stubs method+url with a status and body.
+func (s *StubTransport) Set(method, url string, status int, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.responses == nil {
+		s.responses = map[string]stubResponse{}
+	}
+	s.responses[method+" "+url] = stubResponse{status, body}
+}
+
+// Requests returns "METHOD URL" for every request seen, in order.
+func (s *StubTransport) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *StubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := req.Method + " " + req.URL.String()
+	s.mu.Lock()
+	s.requests = append(s.requests, key)
+	res, ok := s.responses[key]
+	s.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("handlertest: no stub for %s", key)
+	}
+	return &http.Response{
+		StatusCode: res.status,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(res.body)),
+		Request:    req,
+	}, nil
+}
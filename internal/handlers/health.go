@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"multi-kind-app/internal/apierr"
+)
+
+// ──────────── Backend Pings ────────────
+
+const pingTimeout = 2 * time.Second
+
+type pingResult struct {
+	OK        bool    `json:"ok"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// pingHistory is what /healthz?deep=true remembers between calls, so a
+// backend that just recovered still shows why it was failing.
+type pingHistory struct {
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+	LastOKAt    *time.Time `json:"last_ok_at,omitempty"`
+}
+
+// recordPing folds res into name's history and returns the updated copy.
+func (a *App) recordPing(name string, res pingResult) pingHistory {
+	a.pingsMu.Lock()
+	defer a.pingsMu.Unlock()
+	h := a.pingHistory[name]
+	now := time.Now().UTC()
+	if res.OK {
+		h.LastOKAt = &now
+	} else {
+		h.LastError, h.LastErrorAt = res.Error, &now
+	}
+	a.pingHistory[name] = h
+	return h
+}
+
+// backendPings are the Ping of every enabled backend, by name.
+func (a *App) backendPings() map[string]func(ctx context.Context) error {
+	out := map[string]func(ctx context.Context) error{}
+	for name, b := range a.backends {
+		if a.flags.Enabled(name) {
+			out[name] = b.Ping
+		}
+	}
+	return out
+}
+
+// ping runs a single backend ping under its own timeout.
+func ping(ctx context.Context, fn func(context.Context) error) pingResult {
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	start := time.Now()
+	err := fn(ctx)
+	res := pingResult{OK: err == nil, LatencyMs: float64(time.Since(start).Microseconds()) / 1000}
+	if err != nil {
+		res.Error = err.Error()
+	}
+	return res
+}
+
+// pingAll pings every backend concurrently.
+func (a *App) pingAll(ctx context.Context) map[string]pingResult {
+	pings := a.backendPings()
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		out = make(map[string]pingResult, len(pings))
+	)
+	for name, fn := range pings {
+		wg.Add(1)
+		go func(name string, fn func(context.Context) error) {
+			defer wg.Done()
+			res := ping(ctx, fn)
+			a.recordPing(name, res)
+			mu.Lock()
+			out[name] = res
+			mu.Unlock()
+		}(name, fn)
+	}
+	wg.Wait()
+	return out
+}
+
+// handlePingAll — always 200; the per-backend breakdown carries the failures.
+func (a *App) handlePingAll(c *gin.Context) {
+	c.JSON(200, gin.H{"backends": a.pingAll(c.Request.Context())})
+}
+
+// ──────────── Health ────────────
+
+// handleHealthz — liveness by default: 200 without touching any backend.
+// With ?deep=true every backend is pinged in parallel and reported with
+// its latency and last error; the status is "down" (503) when a critical
+// backend fails and "degraded" (200) when only an informational one does.
+func (a *App) handleHealthz(c *gin.Context) {
+	if deep, _ := strconv.ParseBool(c.Query("deep")); !deep {
+		c.JSON(200, gin.H{"status": "ok"})
+		return
+	}
+	type dependency struct {
+		pingResult
+		pingHistory
+		Critical bool `json:"critical"`
+	}
+	results := a.pingAll(c.Request.Context())
+	deps := make(map[string]dependency, len(results))
+	status, code := "ok", 200
+	for name, res := range results {
+		a.pingsMu.Lock()
+		h := a.pingHistory[name]
+		a.pingsMu.Unlock()
+		critical := a.criticalBackends[name]
+		deps[name] = dependency{res, h, critical}
+		switch {
+		case res.OK:
+		case critical:
+			status, code = "down", 503
+		case status == "ok":
+			status = "degraded"
+		}
+	}
+	c.JSON(code, gin.H{"status": status, "dependencies": deps})
+}
+
+// ──────────── Readiness ────────────
+
+// criticalSet turns the configured critical backend names into a set.
+// Unknown names are logged and ignored; disabled backends are dropped.
+func (a *App) criticalSet(names []string) map[string]bool {
+	set := map[string]bool{}
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if _, ok := a.backends[name]; !ok {
+			slog.Warn("CRITICAL_BACKENDS: unknown backend ignored", "backend", name)
+			continue
+		}
+		if a.flags.Enabled(name) {
+			set[name] = true
+		}
+	}
+	return set
+}
+
+// handleReadyz — 200 when every critical backend answers, 503 otherwise.
+func (a *App) handleReadyz(c *gin.Context) {
+	results := a.pingAll(c.Request.Context())
+	ready := true
+	type backendStatus struct {
+		pingResult
+		Critical bool `json:"critical"`
+	}
+	backends := map[string]backendStatus{}
+	for name, res := range results {
+		critical := a.criticalBackends[name]
+		if critical && !res.OK {
+			ready = false
+		}
+		backends[name] = backendStatus{res, critical}
+	}
+	status, code := "ready", 200
+	if !ready {
+		status, code = "not_ready", 503
+	}
+	c.JSON(code, gin.H{"status": status, "backends": backends})
+}
+
+// handleBackendHealth — pings a single named backend: 200 if healthy, 503
+// if not, 404 for names that aren't a backend.
+func (a *App) handleBackendHealth(c *gin.Context) {
+	name := strings.ToLower(c.Param("name"))
+	fn, ok := a.backendPings()[name]
+	if !ok {
+		c.Error(apierr.NotFound("unknown backend: " + name))
+		return
+	}
+	res := ping(c.Request.Context(), fn)
+	a.recordPing(name, res)
+	code := 200
+	if !res.OK {
+		code = 503
+	}
+	c.JSON(code, gin.H{"backend": name, "result": res})
+}
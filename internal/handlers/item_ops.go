@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"multi-kind-app/internal/apierr"
+	"multi-kind-app/internal/service"
+)
+
+// ──────────── Item Operations ────────────
+
+// handleDuplicate — copies an item under a fresh id with " (copy)" appended
+// to its name. The read and the insert are separate operations with no
+// transaction around them (that would need a replica set), so a write or
+// delete landing in between goes unnoticed: the copy is of the item as it
+// was read.
+func (a *App) handleDuplicate(c *gin.Context) {
+	col, err := a.getItemsCol()
+	if err != nil {
+		unavailable(c, "mongo", err)
+		return
+	}
+	id := c.Param("id")
+	ctx := c.Request.Context()
+	if err := a.injectedFault("mongo"); err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	var src Item
+	err = col.FindOne(ctx, liveFilter(bson.M{"_id": id})).Decode(&src)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.Error(apierr.NotFound("not found"))
+		return
+	}
+	if err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+
+	name, err := service.SanitizeName(service.CopyName(src.Name))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	dup := Item{ID: a.items.NewID(), Name: name, Value: src.Value}
+	if _, err := col.InsertOne(ctx, dup); err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	c.JSON(201, gin.H{"id": dup.ID, "source_id": id, "item": dup})
+}
+
+// handleTouch — sets only updated_at to now. MatchedCount, not
+// ModifiedCount, decides the 404 so touching twice in the same millisecond
+// still succeeds.
+func (a *App) handleTouch(c *gin.Context) {
+	col, err := a.getItemsCol()
+	if err != nil {
+		unavailable(c, "mongo", err)
+		return
+	}
+	id := c.Param("id")
+	if err := a.injectedFault("mongo"); err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	res, err := col.UpdateOne(c.Request.Context(), liveFilter(bson.M{"_id": id}), bson.M{"$set": bson.M{"updated_at": now}})
+	if err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	if res.MatchedCount == 0 {
+		c.Error(apierr.NotFound("not found"))
+		return
+	}
+	a.cache.Invalidate("mongo", id)
+	c.JSON(200, gin.H{"id": id, "updated_at": now})
+}
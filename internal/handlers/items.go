@@ -0,0 +1,384 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"multi-kind-app/internal/apierr"
+	"multi-kind-app/internal/service"
+	"multi-kind-app/internal/storage"
+)
+
+// ──────────── Single-DB Handlers ────────────
+
+// handleRedisOnly — ONLY touches Redis. Should produce Kind: "Redis"
+func (a *App) handleRedisOnly(c *gin.Context) {
+	rdb, err := a.getRedis()
+	if err != nil {
+		unavailable(c, "redis", err)
+		return
+	}
+	val, err := service.SanitizeName(c.Param("val"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	ctx := c.Request.Context()
+
+	if err := rdb.Set(ctx, val, val, 10*time.Minute).Err(); err != nil {
+		c.Error(apierr.Wrap(err, "redis SET"))
+		return
+	}
+	res, err := rdb.Get(ctx, val).Result()
+	if err != nil {
+		c.Error(apierr.Wrap(err, "redis GET"))
+		return
+	}
+	c.JSON(200, gin.H{"source": "redis", "value": res})
+}
+
+// handleMongoOnly — ONLY touches Mongo. Should produce Kind: "Mongo"
+func (a *App) handleMongoOnly(c *gin.Context) {
+	col, err := a.getItemsCol()
+	if err != nil {
+		unavailable(c, "mongo", err)
+		return
+	}
+	val, err := service.SanitizeName(c.Param("val"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	ctx := c.Request.Context()
+
+	filter := bson.M{"_id": val}
+	update := bson.M{"$set": bson.M{"_id": val, "value": val}}
+	opts := options.Update().SetUpsert(true)
+	if err := a.injectedFault("mongo"); err != nil {
+		c.Error(apierr.Wrap(err, "mongo upsert"))
+		return
+	}
+	if _, err := col.UpdateOne(ctx, filter, update, opts); err != nil {
+		c.Error(apierr.Wrap(err, "mongo upsert"))
+		return
+	}
+	a.cache.Invalidate("mongo", val)
+	var doc bson.M
+	if err := col.FindOne(ctx, filter).Decode(&doc); err != nil {
+		c.Error(apierr.Wrap(err, "mongo find"))
+		return
+	}
+	c.JSON(200, gin.H{"source": "mongo", "doc": doc})
+}
+
+// handleHTTPOnly — makes an external HTTP call. Should produce Kind: "Http"
+func (a *App) handleHTTPOnly(c *gin.Context) {
+	if a.cfg.DisableOutboundHTTP {
+		c.JSON(200, gin.H{"source": "http", "disabled": true})
+		return
+	}
+	resp, err := a.fetchWithRetry(c.Request.Context(), a.cfg.UpstreamURL, a.cfg.HTTPRetries)
+	if err != nil {
+		c.Error(apierr.Upstream(err, "http GET"))
+		return
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	c.Data(200, "application/json", body)
+}
+
+// ──────────── Multi-DB Handlers ────────────
+
+// The /api/item handlers go through the "mongo" store of a.items, like the
+// /stores routes, and keep the Redis copy of each value alongside.
+
+func (a *App) createItem(c *gin.Context) {
+	rdb, err := a.getRedis()
+	if err != nil {
+		unavailable(c, "redis", err)
+		return
+	}
+	var item Item
+	if err := c.ShouldBindJSON(&item); err != nil {
+		c.Error(apierr.BadRequest(err.Error()))
+		return
+	}
+	ctx := c.Request.Context()
+
+	if err := a.injectedFault("mongo"); err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	item, _, err = a.items.Replace(ctx, "mongo", item)
+	if err != nil {
+		if a.useFallback(err) {
+			a.memItems.Put(item)
+			c.JSON(200, gin.H{"status": "created", "id": item.ID, "backend": "memory"})
+			return
+		}
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	defer a.cache.Invalidate("mongo", item.ID)
+	if err := rdb.Set(ctx, "item:"+item.ID, item.Value, 10*time.Minute).Err(); err != nil {
+		c.Error(apierr.Wrap(err, "redis"))
+		return
+	}
+	c.JSON(200, gin.H{"status": "created", "id": item.ID})
+}
+
+func (a *App) getItem(c *gin.Context) {
+	rdbRead, err := a.getRedisRead()
+	if err != nil {
+		unavailable(c, "redis", err)
+		return
+	}
+	id := c.Param("id")
+	ctx := c.Request.Context()
+
+	if body, ok := a.cache.Get("mongo", id); ok {
+		c.Header("X-Cache", "HIT")
+		c.JSON(200, body)
+		return
+	}
+
+	if err := a.injectedFault("mongo"); err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	// Concurrent misses for the same key share one Mongo and Redis round
+	// trip. The lookup is detached from the leader's cancellation so a
+	// client hanging up doesn't fail every waiter.
+	v, err, shared := a.itemReads.Do("mongo:"+id, func() (any, error) {
+		ctx := context.WithoutCancel(ctx)
+		item, err := a.items.Get(ctx, "mongo", id)
+		if err != nil {
+			return nil, err
+		}
+		cached, _ := rdbRead.Get(ctx, "item:"+id).Result()
+		return gin.H{"item": item, "redis_cached": cached}, nil
+	})
+	if err != nil {
+		if a.useFallback(err) {
+			if item, ok := a.memItems.Get(id); ok {
+				c.JSON(200, gin.H{"item": item, "backend": "memory"})
+				return
+			}
+		}
+		if errors.Is(err, storage.ErrNotFound) {
+			c.Error(apierr.NotFound("not found"))
+			return
+		}
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	body := v.(gin.H)
+	a.cache.Put("mongo", id, body)
+	c.Header("X-Cache", "MISS")
+	c.Header("X-Coalesced", strconv.FormatBool(shared))
+	c.JSON(200, body)
+}
+
+// putItem — full replace with create-if-missing: 201 when the id was new,
+// 200 when an existing document was replaced. A soft-deleted item stays
+// deleted.
+func (a *App) putItem(c *gin.Context) {
+	rdb, err := a.getRedis()
+	if err != nil {
+		unavailable(c, "redis", err)
+		return
+	}
+	id := c.Param("id")
+	var item Item
+	if err := c.ShouldBindJSON(&item); err != nil {
+		c.Error(apierr.BadRequest(err.Error()))
+		return
+	}
+	if item.ID != "" && item.ID != id {
+		c.Error(apierr.BadRequest("body id does not match path id"))
+		return
+	}
+	item.ID = id
+	ctx := c.Request.Context()
+
+	if err := a.injectedFault("mongo"); err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	item, created, err := a.items.Replace(ctx, "mongo", item)
+	if err != nil {
+		if a.useFallback(err) {
+			_, existed := a.memItems.Get(id)
+			a.memItems.Put(item)
+			status := 201
+			if existed {
+				status = 200
+			}
+			c.JSON(status, gin.H{"item": item, "backend": "memory"})
+			return
+		}
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	defer a.cache.Invalidate("mongo", id)
+	if err := rdb.Set(ctx, "item:"+id, item.Value, 10*time.Minute).Err(); err != nil {
+		c.Error(apierr.Wrap(err, "redis"))
+		return
+	}
+	if created {
+		c.JSON(201, gin.H{"status": "created", "item": item})
+		return
+	}
+	c.JSON(200, gin.H{"status": "replaced", "item": item})
+}
+
+// liveFilter narrows filter to items that aren't soft-deleted.
+func liveFilter(filter bson.M) bson.M {
+	filter["deleted_at"] = bson.M{"$exists": false}
+	return filter
+}
+
+const maxBatchIDs = 50
+
+// getItems — fetches every ?id= in one lookup and returns the items in the
+// order they were requested; ids with no live item are listed under
+// "missing".
+func (a *App) getItems(c *gin.Context) {
+	ids := c.QueryArray("id")
+	if len(ids) == 0 || len(ids) > maxBatchIDs {
+		c.Error(apierr.BadRequest("pass between 1 and 50 ?id= parameters"))
+		return
+	}
+	ctx := c.Request.Context()
+
+	if err := a.injectedFault("mongo"); err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	items, missing, err := a.items.GetMany(ctx, "mongo", ids)
+	if err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	c.JSON(200, gin.H{"items": items, "missing": missing})
+}
+
+// listQuery reads ?limit=, ?offset= and ?sort=, clamping limit to
+// maxPageSize, plus the ?name=, ?created_after= and ?created_before=
+// filters (RFC 3339). sort defaults to def.
+func (a *App) listQuery(c *gin.Context, def storage.Sort) (storage.ListQuery, error) {
+	var q storage.ListQuery
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", strconv.FormatInt(a.cfg.MaxPageSize, 10)), 10, 64)
+	if err != nil || limit <= 0 {
+		return q, fmt.Errorf("invalid limit")
+	}
+	q.Offset, err = strconv.ParseInt(c.DefaultQuery("offset", "0"), 10, 64)
+	if err != nil || q.Offset < 0 {
+		return q, fmt.Errorf("invalid offset")
+	}
+	q.Limit = min(limit, a.cfg.MaxPageSize)
+	q.Sort = def
+	if s := c.Query("sort"); s != "" {
+		if q.Sort, err = storage.ParseSort(s); err != nil {
+			return q, err
+		}
+	}
+	q.Name = strings.TrimSpace(c.Query("name"))
+	if v := c.Query("include_deleted"); v != "" {
+		if q.IncludeDeleted, err = strconv.ParseBool(v); err != nil {
+			return q, fmt.Errorf("invalid include_deleted: want true or false")
+		}
+	}
+	for param, t := range map[string]*time.Time{"created_after": &q.CreatedAfter, "created_before": &q.CreatedBefore} {
+		if v := c.Query(param); v != "" {
+			if *t, err = time.Parse(time.RFC3339Nano, v); err != nil {
+				return q, fmt.Errorf("invalid %s: want an RFC 3339 time", param)
+			}
+		}
+	}
+	return q, nil
+}
+
+// listItems — one filtered page of items, ordered by id unless ?sort=
+// says otherwise, with the total count.
+func (a *App) listItems(c *gin.Context) {
+	q, err := a.listQuery(c, storage.SortID)
+	if err != nil {
+		c.Error(apierr.BadRequest(err.Error()))
+		return
+	}
+	if err := a.injectedFault("mongo"); err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	page, err := a.items.List(c.Request.Context(), "mongo", q)
+	if err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	c.JSON(200, page)
+}
+
+type lineError struct {
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+const maxIngestLine = 1 << 20
+
+// ingestItems — reads an NDJSON body line by line and creates each item in
+// the mongo store. Bad lines are reported and skipped; they never abort the
+// rest of the body.
+func (a *App) ingestItems(c *gin.Context) {
+	ctx := c.Request.Context()
+	sc := bufio.NewScanner(c.Request.Body)
+	sc.Buffer(make([]byte, 0, 64*1024), maxIngestLine)
+
+	inserted := 0
+	errs := []lineError{}
+	for line := 1; sc.Scan(); line++ {
+		raw := bytes.TrimSpace(sc.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		var item Item
+		if err := json.Unmarshal(raw, &item); err != nil {
+			errs = append(errs, lineError{line, "invalid JSON: " + err.Error()})
+			continue
+		}
+		if item.ID == "" {
+			errs = append(errs, lineError{line, "missing id"})
+			continue
+		}
+		if err := a.injectedFault("mongo"); err != nil {
+			errs = append(errs, lineError{line, apierr.Wrap(err, "mongo").Detail})
+			continue
+		}
+		if _, err := a.items.Create(ctx, "mongo", item); err != nil {
+			slog.Warn("ingest line failed", "request_id", c.GetString("request_id"), "line", line, "err", err)
+			errs = append(errs, lineError{line, apierr.Wrap(err, "mongo").Detail})
+			continue
+		}
+		a.cache.Invalidate("mongo", item.ID)
+		inserted++
+	}
+	if err := sc.Err(); err != nil {
+		// An over-long line or a broken body stops the scan; report it and
+		// keep what was already inserted.
+		errs = append(errs, lineError{0, "read body: " + err.Error()})
+	}
+	c.JSON(200, gin.H{"inserted": inserted, "failed": len(errs), "errors": errs})
+}
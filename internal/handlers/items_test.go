@@ -0,0 +1,48 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"multi-kind-app/internal/apierr"
+	"multi-kind-app/internal/handlers/handlertest"
+	"multi-kind-app/internal/storage"
+)
+
+type getItemBody struct {
+	Item        storage.Item `json:"item"`
+	RedisCached string       `json:"redis_cached"`
+}
+
+func TestItemCacheInvalidatedOnWrite(t *testing.T) {
+	h := handlertest.New(t)
+	get := func(wantCache, wantValue string) {
+		t.Helper()
+		w := h.Do(http.MethodGet, "/api/item/a", nil)
+		body := handlertest.Decode[getItemBody](t, w)
+		if w.Code != http.StatusOK || w.Header().Get("X-Cache") != wantCache || body.Item.Value != wantValue {
+			t.Fatalf("get: got %d X-Cache %q %s, want %s %s", w.Code, w.Header().Get("X-Cache"), w.Body, wantCache, wantValue)
+		}
+	}
+
+	if w := h.Do(http.MethodPut, "/api/item/a", storage.Item{Name: "a", Value: "1"}); w.Code != http.StatusCreated {
+		t.Fatalf("create: got %d %s", w.Code, w.Body)
+	}
+	get("MISS", "1")
+	get("HIT", "1")
+
+	if w := h.Do(http.MethodPut, "/api/item/a", storage.Item{Name: "a", Value: "2"}); w.Code != http.StatusOK {
+		t.Fatalf("replace: got %d %s", w.Code, w.Body)
+	}
+	get("MISS", "2")
+
+	if w := h.Do(http.MethodDelete, "/stores/mongo/items/a", nil); w.Code != http.StatusNoContent {
+		t.Fatalf("delete: got %d %s", w.Code, w.Body)
+	}
+	wantProblem(t, h.Do(http.MethodGet, "/api/item/a", nil), http.StatusNotFound, apierr.CodeNotFound)
+
+	if w := h.Do(http.MethodPost, "/stores/mongo/items/a/restore", nil); w.Code != http.StatusOK {
+		t.Fatalf("restore: got %d %s", w.Code, w.Body)
+	}
+	get("MISS", "2")
+}
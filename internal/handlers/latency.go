@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"math"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ──────────── Latency Histograms ────────────
+
+// latencyBounds are the bucket upper bounds in milliseconds; a final
+// overflow bucket catches everything slower.
+var latencyBounds = [...]float64{0.1, 0.25, 0.5, 1, 2, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+type histogram struct {
+	buckets [len(latencyBounds) + 1]atomic.Int64 // last = overflow
+	count   atomic.Int64
+	sumUs   atomic.Int64
+}
+
+func (h *histogram) observe(d time.Duration) {
+	ms := float64(d.Microseconds()) / 1000
+	i := sort.SearchFloat64s(latencyBounds[:], ms)
+	h.buckets[i].Add(1)
+	h.count.Add(1)
+	h.sumUs.Add(d.Microseconds())
+}
+
+// quantile estimates the q-th quantile by linear interpolation inside the
+// bucket holding the target rank. Ranks landing in the overflow bucket
+// report its lower bound, since it has no upper one.
+func (h *histogram) quantile(q float64, counts []int64, total int64) float64 {
+	rank := q * float64(total)
+	var seen int64
+	for i, n := range counts {
+		if n == 0 || float64(seen+n) < rank {
+			seen += n
+			continue
+		}
+		lower := 0.0
+		if i > 0 {
+			lower = latencyBounds[i-1]
+		}
+		if i == len(latencyBounds) {
+			return lower
+		}
+		frac := (rank - float64(seen)) / float64(n)
+		return lower + frac*(latencyBounds[i]-lower)
+	}
+	return 0
+}
+
+type latencySummary struct {
+	Count  int64   `json:"count"`
+	MeanMs float64 `json:"mean_ms"`
+	P50Ms  float64 `json:"p50_ms"`
+	P95Ms  float64 `json:"p95_ms"`
+	P99Ms  float64 `json:"p99_ms"`
+}
+
+func (h *histogram) summary() latencySummary {
+	counts := make([]int64, len(h.buckets))
+	var total int64
+	for i := range h.buckets {
+		counts[i] = h.buckets[i].Load()
+		total += counts[i]
+	}
+	if total == 0 {
+		return latencySummary{}
+	}
+	round := func(v float64) float64 { return math.Round(v*1000) / 1000 }
+	return latencySummary{
+		Count:  total,
+		MeanMs: round(float64(h.sumUs.Load()) / 1000 / float64(h.count.Load())),
+		P50Ms:  round(h.quantile(0.50, counts, total)),
+		P95Ms:  round(h.quantile(0.95, counts, total)),
+		P99Ms:  round(h.quantile(0.99, counts, total)),
+	}
+}
+
+func (a *App) observeLatency(c *gin.Context, d time.Duration) {
+	route := c.FullPath()
+	if route == "" {
+		route = "unmatched"
+	}
+	key := c.Request.Method + " " + route
+	v, ok := a.latencies.Load(key)
+	if !ok {
+		v, _ = a.latencies.LoadOrStore(key, &histogram{})
+	}
+	v.(*histogram).observe(d)
+}
+
+// handleLatencyStats — p50/p95/p99 per endpoint, estimated from buckets.
+func (a *App) handleLatencyStats(c *gin.Context) {
+	out := map[string]latencySummary{}
+	a.latencies.Range(func(k, v any) bool {
+		out[k.(string)] = v.(*histogram).summary()
+		return true
+	})
+	c.JSON(200, gin.H{"bucket_bounds_ms": latencyBounds, "endpoints": out})
+}
+
+// handleMetricsReset — drops every histogram; they are recreated on the
+// next request to each endpoint.
+func (a *App) handleMetricsReset(c *gin.Context) {
+	n := 0
+	a.latencies.Range(func(k, _ any) bool {
+		a.latencies.Delete(k)
+		n++
+		return true
+	})
+	c.JSON(200, gin.H{"reset": n})
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ──────────── Logging ────────────
+
+type loggerKey struct{}
+
+// withLogger stores l in ctx so code below the handler can log with the
+// request's attributes.
+func withLogger(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// ctxLog returns the logger stored in ctx, or the default logger.
+func ctxLog(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+// reqLog returns the logger for this request, tagged with its request id.
+func reqLog(c *gin.Context) *slog.Logger {
+	return ctxLog(c.Request.Context())
+}
+
+// requestLogger replaces gin.Logger with one structured record per request.
+// It also feeds the per-endpoint latency histograms.
+func (a *App) requestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		a.observeLatency(c, time.Since(start))
+		reqLog(c).Info("request",
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", c.Writer.Status(),
+			"duration_ms", time.Since(start).Milliseconds(),
+			"client_ip", c.ClientIP(),
+		)
+	}
+}
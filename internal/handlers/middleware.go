@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/sync/semaphore"
+
+	"multi-kind-app/internal/apierr"
+)
+
+// ──────────── Concurrency Limit ────────────
+
+// concurrencyLimit caps the number of in-flight requests. When the cap is
+// reached the request is rejected immediately with 503 instead of queueing,
+// so backpressure reaches the client before the DB pools are exhausted.
+func (a *App) concurrencyLimit(limit int64) gin.HandlerFunc {
+	var sem *semaphore.Weighted
+	if limit > 0 {
+		sem = semaphore.NewWeighted(limit)
+	}
+	return func(c *gin.Context) {
+		if sem != nil {
+			if !sem.TryAcquire(1) {
+				a.rejected.Add(1)
+				c.Header("Retry-After", "1")
+				apierr.Abort(c, apierr.New(503, apierr.CodeOverloaded, "server busy: too many concurrent requests"))
+				return
+			}
+			defer sem.Release(1)
+		}
+		a.inFlight.Add(1)
+		defer a.inFlight.Add(-1)
+		c.Next()
+	}
+}
+
+// handleStats — in-process counters, no backend calls.
+func (a *App) handleStats(c *gin.Context) {
+	c.JSON(200, gin.H{
+		"in_flight":         a.inFlight.Load(),
+		"max_concurrent":    a.cfg.MaxConcurrent,
+		"rejected":          a.rejected.Load(),
+		"injected_failures": a.injectedCounts(),
+	})
+}
+
+// handlePoolStats — connection pool counters for the primary and read
+// Redis clients. Both report the same pool when no replica is configured.
+func (a *App) handlePoolStats(c *gin.Context) {
+	rdb, err := a.getRedis()
+	if err != nil {
+		unavailable(c, "redis", err)
+		return
+	}
+	rdbRead, err := a.getRedisRead()
+	if err != nil {
+		unavailable(c, "redis", err)
+		return
+	}
+	c.JSON(200, gin.H{
+		"redis_primary": rdb.PoolStats(),
+		"redis_read":    rdbRead.PoolStats(),
+		"read_split":    rdbRead != rdb,
+	})
+}
+
+// ──────────── Request ID ────────────
+
+const requestIDHeader = "X-Request-ID"
+
+// requestID propagates the caller's X-Request-ID, or mints one, so logs and
+// error bodies can be correlated with a specific request.
+func requestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestIDHeader)
+		if id == "" {
+			id = newRequestID()
+		}
+		c.Set("request_id", id)
+		c.Request = c.Request.WithContext(withLogger(c.Request.Context(), slog.Default().With("request_id", id)))
+		c.Header(requestIDHeader, id)
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	return randomHex(16)
+}
+
+// randomHex returns n random bytes hex-encoded, falling back to a timestamp
+// if the system RNG is unavailable.
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
+
+// ──────────── Panic Recovery ────────────
+
+// recovery replaces gin's default recovery: the panic and stack are logged as
+// a single structured record, and the client gets an INTERNAL problem carrying
+// the request id. The panic message is only echoed back in debug mode.
+func recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			reqLog(c).Error("panic recovered",
+				"panic", fmt.Sprint(rec),
+				"stack", string(debug.Stack()),
+				"method", c.Request.Method,
+				"path", c.Request.URL.Path,
+			)
+
+			detail := "internal error"
+			if gin.IsDebugging() {
+				detail = fmt.Sprint(rec)
+			}
+			apierr.Abort(c, apierr.New(500, apierr.CodeInternal, detail))
+		}()
+		c.Next()
+	}
+}
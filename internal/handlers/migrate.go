@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"multi-kind-app/internal/apierr"
+	"multi-kind-app/internal/storage/mongostore"
+)
+
+// ──────────── Schema Migrations ────────────
+
+// handleMigrate — applies pending migrations on demand and reports what ran.
+// A failure still returns the versions applied before it.
+func (a *App) handleMigrate(c *gin.Context) {
+	mdb, err := a.getMongo()
+	if err != nil {
+		unavailable(c, "mongo", err)
+		return
+	}
+	ctx := c.Request.Context()
+	applied, err := mongostore.Migrate(ctx, mdb)
+	version, verr := mongostore.SchemaVersion(ctx, mdb)
+	var schemaVersion any = version
+	if verr != nil {
+		schemaVersion = nil
+	}
+	if err != nil {
+		c.Error(apierr.Wrap(err, "mongo migrate").With("applied", applied).With("schema_version", schemaVersion))
+		return
+	}
+	c.JSON(200, gin.H{"applied": applied, "schema_version": schemaVersion})
+}
@@ -0,0 +1,325 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"multi-kind-app/internal/apierr"
+	"multi-kind-app/internal/service"
+	"multi-kind-app/internal/storage/mongostore"
+)
+
+// ──────────── Mongo Item Handlers ────────────
+
+// docIDParam parses :id as an _id. A 24-digit hex id is an ObjectID; any
+// other id is matched as the string _id id_mode uuid stores, so documents
+// minted in either mode stay reachable after IDMode changes.
+func docIDParam(c *gin.Context) any {
+	id := c.Param("id")
+	if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+		return oid
+	}
+	return id
+}
+
+// newDocID is the _id of an inserted document: an ObjectID, or a UUID
+// string in id_mode uuid.
+func (a *App) newDocID() any {
+	if a.cfg.IDMode == "uuid" {
+		return a.items.NewID()
+	}
+	return primitive.NewObjectID()
+}
+
+// idString renders an _id from docIDParam or newDocID.
+func idString(id any) string {
+	if oid, ok := id.(primitive.ObjectID); ok {
+		return oid.Hex()
+	}
+	return id.(string)
+}
+
+// docBody binds a JSON object body. _id, version and deleted_at are always
+// the server's, so a body carrying any of them is rejected; a string name is
+// sanitised like everywhere else.
+func docBody(c *gin.Context) (bson.M, bool) {
+	var doc bson.M
+	if err := c.ShouldBindJSON(&doc); err != nil {
+		c.Error(apierr.BadRequest("body must be a JSON object: " + err.Error()))
+		return nil, false
+	}
+	for _, field := range []string{"_id", versionField, "deleted_at"} {
+		if _, ok := doc[field]; ok {
+			c.Error(apierr.BadRequest(field + " is assigned by the server"))
+			return nil, false
+		}
+	}
+	if name, ok := doc["name"].(string); ok {
+		clean, err := service.SanitizeName(name)
+		if err != nil {
+			c.Error(err)
+			return nil, false
+		}
+		doc["name"] = clean
+	}
+	return doc, true
+}
+
+// versionField is the revision counter on /mongo/items documents. Insert
+// sets it to 1 and every PUT or PATCH bumps it; the ETag is its value.
+const versionField = "version"
+
+// docVersion is doc's version; 0 for documents written before versioning.
+func docVersion(doc bson.M) int64 {
+	switch v := doc[versionField].(type) {
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	}
+	return 0
+}
+
+func setETag(c *gin.Context, version int64) {
+	c.Header("ETag", `"`+strconv.FormatInt(version, 10)+`"`)
+}
+
+// ifMatch reads the version a write was based on from If-Match, writing a
+// 428 when the header is missing. Anything but one of our strong ETags
+// yields -1, which no document has, so the write ends in a 412.
+func ifMatch(c *gin.Context) (int64, bool) {
+	h := c.GetHeader("If-Match")
+	if h == "" {
+		c.Error(apierr.New(http.StatusPreconditionRequired, apierr.CodePreconditionRequired,
+			"If-Match is required: send the ETag of the version you are changing"))
+		return 0, false
+	}
+	if len(h) < 2 || h[0] != '"' || h[len(h)-1] != '"' {
+		return -1, true
+	}
+	v, err := strconv.ParseInt(h[1:len(h)-1], 10, 64)
+	if err != nil {
+		return -1, true
+	}
+	return v, true
+}
+
+// versionFilter matches id only while it is live and still at version.
+func versionFilter(id any, version int64) bson.M {
+	if version == 0 {
+		return liveFilter(bson.M{"_id": id, versionField: bson.M{"$exists": false}})
+	}
+	return liveFilter(bson.M{"_id": id, versionField: version})
+}
+
+// versionMismatch explains why a versionFilter write matched nothing: a
+// 404 when id is gone or soft-deleted, otherwise a 412 carrying the
+// current ETag.
+func versionMismatch(c *gin.Context, col *mongo.Collection, id any, op string) {
+	var doc bson.M
+	err := col.FindOne(c.Request.Context(), liveFilter(bson.M{"_id": id}),
+		options.FindOne().SetProjection(bson.M{versionField: 1})).Decode(&doc)
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		c.Error(apierr.NotFound("not found"))
+	case err != nil:
+		c.Error(apierr.Wrap(err, op))
+	default:
+		setETag(c, docVersion(doc))
+		c.Error(apierr.New(http.StatusPreconditionFailed, apierr.CodePreconditionFailed,
+			"the document has changed since the ETag in If-Match").With("version", docVersion(doc)))
+	}
+}
+
+// handleMongoInsert — InsertOne with the client's fields at version 1; 201
+// with the generated id (an ObjectID in hex, or a UUID).
+func (a *App) handleMongoInsert(c *gin.Context) {
+	col, err := a.getItemsCol()
+	if err != nil {
+		unavailable(c, "mongo", err)
+		return
+	}
+	doc, ok := docBody(c)
+	if !ok {
+		return
+	}
+	if err := a.injectedFault("mongo"); err != nil {
+		c.Error(apierr.Wrap(err, "mongo insert"))
+		return
+	}
+	id := a.newDocID()
+	doc["_id"] = id
+	doc[versionField] = int64(1)
+	if _, err := col.InsertOne(c.Request.Context(), doc); err != nil {
+		c.Error(apierr.Wrap(err, "mongo insert"))
+		return
+	}
+	doc["_id"] = idString(id)
+	setETag(c, 1)
+	c.JSON(201, gin.H{"inserted_id": idString(id), "doc": doc})
+}
+
+// handleMongoReplace — ReplaceOne: the body becomes the whole document at
+// the next version. If-Match must name the current version.
+func (a *App) handleMongoReplace(c *gin.Context) {
+	col, err := a.getItemsCol()
+	if err != nil {
+		unavailable(c, "mongo", err)
+		return
+	}
+	id := docIDParam(c)
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+	doc, ok := docBody(c)
+	if !ok {
+		return
+	}
+	if err := a.injectedFault("mongo"); err != nil {
+		c.Error(apierr.Wrap(err, "mongo replace"))
+		return
+	}
+	doc[versionField] = version + 1
+	res, err := col.ReplaceOne(c.Request.Context(), versionFilter(id, version), doc)
+	if err != nil {
+		c.Error(apierr.Wrap(err, "mongo replace"))
+		return
+	}
+	if res.MatchedCount == 0 {
+		versionMismatch(c, col, id, "mongo replace")
+		return
+	}
+	// The collection is the one GET /api/item/:id caches from.
+	a.cache.Invalidate("mongo", idString(id))
+	doc["_id"] = idString(id)
+	setETag(c, version+1)
+	c.JSON(200, gin.H{"source": "mongo", "doc": doc})
+}
+
+// mergePatchType is the RFC 7386 media type. Plain application/json is
+// accepted as well, with the same semantics.
+const mergePatchType = "application/merge-patch+json"
+
+// handleMongoUpdate — JSON Merge Patch: null fields are $unset, nested
+// objects are merged through dotted $set paths, and fields the patch omits
+// are left alone. If-Match must name the current version, which a
+// non-empty patch bumps. Answers with the patched document.
+func (a *App) handleMongoUpdate(c *gin.Context) {
+	col, err := a.getItemsCol()
+	if err != nil {
+		unavailable(c, "mongo", err)
+		return
+	}
+	if ct := c.ContentType(); ct != mergePatchType && ct != "application/json" {
+		c.Error(apierr.New(415, apierr.CodeMediaType, "Content-Type must be "+mergePatchType))
+		return
+	}
+	id := docIDParam(c)
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+	patch, ok := docBody(c)
+	if !ok {
+		return
+	}
+	update, err := mongostore.MergePatch(patch)
+	if err != nil {
+		c.Error(apierr.BadRequest(err.Error()))
+		return
+	}
+	if err := a.injectedFault("mongo"); err != nil {
+		c.Error(apierr.Wrap(err, "mongo update"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	var res *mongo.SingleResult
+	if len(update) == 0 {
+		res = col.FindOne(ctx, versionFilter(id, version))
+	} else {
+		update["$inc"] = bson.M{versionField: 1}
+		after := options.FindOneAndUpdate().SetReturnDocument(options.After)
+		res = col.FindOneAndUpdate(ctx, versionFilter(id, version), update, after)
+	}
+	var doc bson.M
+	err = res.Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		versionMismatch(c, col, id, "mongo update")
+		return
+	}
+	if err != nil {
+		c.Error(apierr.Wrap(err, "mongo update"))
+		return
+	}
+	a.cache.Invalidate("mongo", idString(id))
+	doc["_id"] = idString(id)
+	setETag(c, docVersion(doc))
+	c.JSON(200, gin.H{"source": "mongo", "doc": doc})
+}
+
+// handleMongoDelete — soft delete, as the /stores routes do: deleted_at is
+// set and the document stays, so POST /stores/mongo/items/:id/restore can
+// bring a string-keyed one back. 204, or 404 when no live document matched.
+func (a *App) handleMongoDelete(c *gin.Context) {
+	col, err := a.getItemsCol()
+	if err != nil {
+		unavailable(c, "mongo", err)
+		return
+	}
+	id := docIDParam(c)
+	if err := a.injectedFault("mongo"); err != nil {
+		c.Error(apierr.Wrap(err, "mongo delete"))
+		return
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	res, err := col.UpdateOne(c.Request.Context(), liveFilter(bson.M{"_id": id}), bson.M{"$set": bson.M{"deleted_at": now}})
+	if err != nil {
+		c.Error(apierr.Wrap(err, "mongo delete"))
+		return
+	}
+	if res.MatchedCount == 0 {
+		c.Error(apierr.NotFound("not found"))
+		return
+	}
+	a.cache.Invalidate("mongo", idString(id))
+	c.Status(204)
+}
+
+// handleMongoGet — reads one document by id, rendering an ObjectID _id as
+// hex, with its version as the ETag.
+func (a *App) handleMongoGet(c *gin.Context) {
+	col, err := a.getItemsCol()
+	if err != nil {
+		unavailable(c, "mongo", err)
+		return
+	}
+	id := docIDParam(c)
+
+	if err := a.injectedFault("mongo"); err != nil {
+		c.Error(apierr.Wrap(err, "mongo find"))
+		return
+	}
+	var doc bson.M
+	err = col.FindOne(c.Request.Context(), liveFilter(bson.M{"_id": id})).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.Error(apierr.NotFound("not found"))
+		return
+	}
+	if err != nil {
+		c.Error(apierr.Wrap(err, "mongo find"))
+		return
+	}
+	doc["_id"] = idString(id)
+	setETag(c, docVersion(doc))
+	c.JSON(200, gin.H{"source": "mongo", "doc": doc})
+}
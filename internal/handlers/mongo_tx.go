@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"multi-kind-app/internal/apierr"
+	"multi-kind-app/internal/service"
+)
+
+// ──────────── Mongo Transactions ────────────
+
+// errTxInjected aborts a /mongo/tx transaction on request.
+var errTxInjected = errors.New("failure injected by ?fail=true")
+
+// handleMongoTx — inserts an item into items and its audit entry into
+// audit in one multi-document transaction, so either both land or
+// neither does. ?fail=true fails the transaction after both inserts,
+// which makes the driver send abortTransaction. Transactions need a
+// replica set (see mongo_replica_set); a standalone server answers 503.
+func (a *App) handleMongoTx(c *gin.Context) {
+	mdb, err := a.getMongo()
+	if err != nil {
+		unavailable(c, "mongo", err)
+		return
+	}
+	var item Item
+	if err := c.ShouldBindJSON(&item); err != nil {
+		c.Error(apierr.BadRequest(err.Error()))
+		return
+	}
+	if item.Name, err = service.SanitizeName(item.Name); err != nil {
+		c.Error(err)
+		return
+	}
+	if item.ID == "" {
+		item.ID = a.items.NewID()
+	}
+	fail := c.Query("fail") == "true"
+	if err := a.injectedFault("mongo"); err != nil {
+		c.Error(apierr.Wrap(err, "mongo transaction"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	sess, err := mdb.Client().StartSession()
+	if err != nil {
+		c.Error(apierr.Wrap(err, "mongo transaction"))
+		return
+	}
+	defer sess.EndSession(context.WithoutCancel(ctx))
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	item.CreatedAt = &now
+	auditID, err := sess.WithTransaction(ctx, func(sc mongo.SessionContext) (any, error) {
+		if _, err := mdb.Collection("items").InsertOne(sc, item); err != nil {
+			return nil, err
+		}
+		audit := bson.M{"event": "item.create", "item_id": item.ID, "at": now}
+		res, err := mdb.Collection("audit").InsertOne(sc, audit)
+		if err != nil {
+			return nil, err
+		}
+		if fail {
+			return nil, errTxInjected
+		}
+		return res.InsertedID, nil
+	})
+
+	var cmdErr mongo.CommandError
+	switch {
+	case errors.Is(err, errTxInjected):
+		c.Error(apierr.New(500, apierr.CodeInternal, "transaction aborted: "+err.Error()).With("aborted", true))
+	case errors.As(err, &cmdErr) && cmdErr.Code == 20: // IllegalOperation: not a replica set
+		unavailable(c, "mongo", errors.New("transactions need a replica set: "+cmdErr.Message))
+	case err != nil:
+		c.Error(apierr.Wrap(err, "mongo transaction"))
+	default:
+		c.JSON(201, gin.H{"item": item, "audit_id": auditID})
+	}
+}
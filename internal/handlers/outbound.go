@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// ──────────── Outbound HTTP ────────────
+
+const retryBaseDelay = 100 * time.Millisecond
+
+// fetchWithRetry GETs url, retrying network errors and 5xx responses with
+// exponential backoff and jitter. It gives up early rather than sleep past
+// the context deadline. The caller owns the returned body.
+func (a *App) fetchWithRetry(ctx context.Context, url string, attempts int) (*http.Response, error) {
+	var lastErr error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			// base * 2^(i-1), then ±50% jitter.
+			delay := retryBaseDelay << (i - 1)
+			delay = delay/2 + rand.N(delay)
+			if dl, ok := ctx.Deadline(); ok && time.Until(dl) < delay {
+				return nil, fmt.Errorf("giving up before deadline: %w", lastErr)
+			}
+			ctxLog(ctx).Warn("retrying outbound GET", "url", url, "attempt", i+1, "delay_ms", delay.Milliseconds(), "err", lastErr)
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := a.httpClient.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if resp.StatusCode >= 500 {
+			resp.Body.Close()
+			lastErr = fmt.Errorf("upstream returned %d", resp.StatusCode)
+			continue
+		}
+		return resp, nil
+	}
+	return nil, lastErr
+}
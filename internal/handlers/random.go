@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"multi-kind-app/internal/apierr"
+)
+
+// handleRandomItem — one random live item via $sample, after a $match that
+// leaves soft-deleted items out. No live items is a 404.
+func (a *App) handleRandomItem(c *gin.Context) {
+	col, err := a.getItemsCol()
+	if err != nil {
+		unavailable(c, "mongo", err)
+		return
+	}
+	ctx := c.Request.Context()
+	if err := a.injectedFault("mongo"); err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	cur, err := col.Aggregate(ctx, bson.A{
+		bson.M{"$match": liveFilter(bson.M{})},
+		bson.M{"$sample": bson.M{"size": 1}},
+	})
+	if err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	defer cur.Close(ctx)
+	if !cur.Next(ctx) {
+		if err := cur.Err(); err != nil {
+			c.Error(apierr.Wrap(err, "mongo"))
+			return
+		}
+		c.Error(apierr.NotFound("no items"))
+		return
+	}
+	var item Item
+	if err := cur.Decode(&item); err != nil {
+		c.Error(apierr.Wrap(err, "mongo decode"))
+		return
+	}
+	c.JSON(200, gin.H{"item": item})
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	"multi-kind-app/internal/apierr"
+)
+
+// ──────────── Redis Bulk Cleanup ────────────
+
+const (
+	scanBatch       = 100
+	scanDeleteLimit = 1000
+)
+
+// scanDeletePrefixes are the demo keyspaces handleScanDelete may clear. A
+// prefix must fall inside one of them, so sessions, the repository keys
+// and the lease-expiry index can't be wiped by an unauthenticated caller.
+var scanDeletePrefixes = []string{"item:", hotPrefix, "bitmap:", leasePrefix, "diff:", kvPrefix}
+
+// globEscape escapes Redis MATCH metacharacters so a prefix is matched
+// literally.
+var globEscape = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+
+// handleScanDelete — removes keys starting with :prefix using SCAN + UNLINK so
+// Redis is never blocked. At most scanDeleteLimit keys are removed per call;
+// a non-zero "cursor" in the response means more may remain — pass it back as
+// ?cursor= to continue. A prefix outside scanDeletePrefixes is a 403.
+func (a *App) handleScanDelete(c *gin.Context) {
+	prefix := c.Param("prefix")
+	if !slices.ContainsFunc(scanDeletePrefixes, func(p string) bool { return strings.HasPrefix(prefix, p) }) {
+		c.Error(apierr.New(403, apierr.CodeForbidden, "prefix must start with one of: "+strings.Join(scanDeletePrefixes, ", ")))
+		return
+	}
+	rdb, err := a.getRedis()
+	if err != nil {
+		unavailable(c, "redis", err)
+		return
+	}
+	ctx := c.Request.Context()
+	pattern := globEscape.Replace(prefix) + "*"
+
+	cursor, err := strconv.ParseUint(c.DefaultQuery("cursor", "0"), 10, 64)
+	if err != nil {
+		c.Error(apierr.BadRequest("invalid cursor"))
+		return
+	}
+
+	var removed int64
+	for {
+		keys, next, err := rdb.Scan(ctx, cursor, pattern, scanBatch).Result()
+		if err != nil {
+			c.Error(apierr.Wrap(err, "redis SCAN").With("removed", removed).With("cursor", cursor))
+			return
+		}
+		if len(keys) > 0 {
+			n, err := rdb.Unlink(ctx, keys...).Result()
+			if err != nil {
+				c.Error(apierr.Wrap(err, "redis UNLINK").With("removed", removed).With("cursor", cursor))
+				return
+			}
+			removed += n
+		}
+		cursor = next
+		if cursor == 0 || removed >= scanDeleteLimit {
+			break
+		}
+	}
+	c.JSON(200, gin.H{"removed": removed, "cursor": cursor, "done": cursor == 0})
+}
+
+// ──────────── Redis Bitmaps ────────────
+
+// maxBitOffset bounds bitmaps to 128 KiB so a single request can't make
+// Redis allocate the 512 MiB a maximal offset would need.
+const maxBitOffset = 1<<20 - 1
+
+// handleBitmap — SETBIT on bitmap:<key>; returns the bit's previous value.
+func (a *App) handleBitmap(c *gin.Context) {
+	rdb, err := a.getRedis()
+	if err != nil {
+		unavailable(c, "redis", err)
+		return
+	}
+	var req struct {
+		Offset *int64 `json:"offset"`
+		Value  *int   `json:"value"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.Offset == nil {
+		c.Error(apierr.BadRequest("body must be {\"offset\": n, \"value\": 0|1}"))
+		return
+	}
+	if *req.Offset < 0 || *req.Offset > maxBitOffset {
+		c.Error(apierr.BadRequest("offset must be between 0 and " + strconv.Itoa(maxBitOffset)))
+		return
+	}
+	value := 1
+	if req.Value != nil {
+		value = *req.Value
+	}
+	if value != 0 && value != 1 {
+		c.Error(apierr.BadRequest("value must be 0 or 1"))
+		return
+	}
+
+	prev, err := rdb.SetBit(c.Request.Context(), "bitmap:"+c.Param("key"), *req.Offset, value).Result()
+	if err != nil {
+		c.Error(apierr.Wrap(err, "redis SETBIT"))
+		return
+	}
+	c.JSON(200, gin.H{"key": c.Param("key"), "offset": *req.Offset, "value": value, "previous": prev})
+}
+
+// handleBitmapCount — BITCOUNT on bitmap:<key>.
+func (a *App) handleBitmapCount(c *gin.Context) {
+	rdbRead, err := a.getRedisRead()
+	if err != nil {
+		unavailable(c, "redis", err)
+		return
+	}
+	n, err := rdbRead.BitCount(c.Request.Context(), "bitmap:"+c.Param("key"), nil).Result()
+	if err != nil {
+		c.Error(apierr.Wrap(err, "redis BITCOUNT"))
+		return
+	}
+	c.JSON(200, gin.H{"key": c.Param("key"), "count": n})
+}
+
+// ──────────── Application-Managed Expiry ────────────
+
+// Leases are plain keys with no Redis TTL. Their logical expiry lives in a
+// companion hash (key → unix seconds) and is enforced by the reaper.
+const (
+	leasePrefix    = "lease:"
+	leaseExpiryKey = "lease-expiry"
+)
+
+// handleLeaseCreate — SET lease:<name> and record its logical expiry.
+func (a *App) handleLeaseCreate(c *gin.Context) {
+	rdb, err := a.getRedis()
+	if err != nil {
+		unavailable(c, "redis", err)
+		return
+	}
+	var req struct {
+		Value     string `json:"value"`
+		ExpiresIn int64  `json:"expires_in_seconds"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.ExpiresIn <= 0 {
+		c.Error(apierr.BadRequest("body must be {\"value\": ..., \"expires_in_seconds\": n > 0}"))
+		return
+	}
+	key := leasePrefix + c.Param("name")
+	expiry := time.Now().Unix() + req.ExpiresIn
+
+	_, err = rdb.TxPipelined(c.Request.Context(), func(p redis.Pipeliner) error {
+		p.Set(c.Request.Context(), key, req.Value, 0)
+		p.HSet(c.Request.Context(), leaseExpiryKey, key, expiry)
+		return nil
+	})
+	if err != nil {
+		c.Error(apierr.Wrap(err, "redis"))
+		return
+	}
+	c.JSON(201, gin.H{"key": key, "expires_at": expiry})
+}
+
+// handleReap — walks the expiry hash with HSCAN and removes every lease whose
+// logical expiry has passed. ?batch= sets the HSCAN page size (default 100).
+func (a *App) handleReap(c *gin.Context) {
+	rdb, err := a.getRedis()
+	if err != nil {
+		unavailable(c, "redis", err)
+		return
+	}
+	ctx := c.Request.Context()
+	batch, err := strconv.ParseInt(c.DefaultQuery("batch", "100"), 10, 64)
+	if err != nil || batch <= 0 || batch > 1000 {
+		c.Error(apierr.BadRequest("batch must be between 1 and 1000"))
+		return
+	}
+	now := time.Now().Unix()
+
+	var (
+		reaped  int64
+		scanned int64
+		cursor  uint64
+	)
+	for {
+		kv, next, err := rdb.HScan(ctx, leaseExpiryKey, cursor, "", batch).Result()
+		if err != nil {
+			c.Error(apierr.Wrap(err, "redis HSCAN").With("reaped", reaped))
+			return
+		}
+		expired := []string{}
+		for i := 0; i+1 < len(kv); i += 2 {
+			scanned++
+			exp, err := strconv.ParseInt(kv[i+1], 10, 64)
+			if err == nil && exp <= now {
+				expired = append(expired, kv[i])
+			}
+		}
+		if len(expired) > 0 {
+			if err := rdb.Unlink(ctx, expired...).Err(); err != nil {
+				c.Error(apierr.Wrap(err, "redis UNLINK").With("reaped", reaped))
+				return
+			}
+			if err := rdb.HDel(ctx, leaseExpiryKey, expired...).Err(); err != nil {
+				c.Error(apierr.Wrap(err, "redis HDEL").With("reaped", reaped))
+				return
+			}
+			reaped += int64(len(expired))
+		}
+		if cursor = next; cursor == 0 {
+			break
+		}
+	}
+	c.JSON(200, gin.H{"reaped": reaped, "scanned": scanned})
+}
@@ -0,0 +1,16 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"multi-kind-app/internal/apierr"
+	"multi-kind-app/internal/handlers/handlertest"
+)
+
+func TestScanDeleteRejectsNonDemoPrefixes(t *testing.T) {
+	h := handlertest.New(t)
+	for _, prefix := range []string{"session:", "repo:", "item", "lease-expiry", "x"} {
+		wantProblem(t, h.Do(http.MethodDelete, "/redis/prefix/"+prefix, nil), http.StatusForbidden, apierr.CodeForbidden)
+	}
+}
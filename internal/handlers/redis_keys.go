@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	"multi-kind-app/internal/apierr"
+)
+
+// ──────────── Generic Key CRUD ────────────
+
+// kvPrefix namespaces the keys these routes manage: :key "a" is the Redis
+// key "kv:a", so no request can reach sessions, leases or the repository
+// and index keys.
+const kvPrefix = "kv:"
+
+// keyParam is :key, rejected with 422 when it has control characters or is
+// empty. Handlers address the Redis key kvPrefix+key.
+func keyParam(c *gin.Context) (string, bool) {
+	key := c.Param("key")
+	if key == "" {
+		c.Error(apierr.Invalid("key must not be empty"))
+		return "", false
+	}
+	for _, r := range key {
+		if unicode.IsControl(r) {
+			c.Error(apierr.Invalid("key contains a control character"))
+			return "", false
+		}
+	}
+	return key, true
+}
+
+// ttlSeconds renders a TTL reply: nil for a key with no expiry.
+func ttlSeconds(ttl time.Duration) any {
+	if ttl < 0 {
+		return nil
+	}
+	return int64(ttl / time.Second)
+}
+
+// handleKeyPut — SET with an optional TTL; ttl_seconds 0 or absent keeps
+// the key until it is deleted.
+func (a *App) handleKeyPut(c *gin.Context) {
+	rdb, err := a.getRedis()
+	if err != nil {
+		unavailable(c, "redis", err)
+		return
+	}
+	key, ok := keyParam(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Value *string `json:"value"`
+		TTL   int64   `json:"ttl_seconds"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.Value == nil || req.TTL < 0 {
+		c.Error(apierr.BadRequest("body must be {\"value\": \"...\", \"ttl_seconds\": n >= 0}"))
+		return
+	}
+	ttl := time.Duration(req.TTL) * time.Second
+	if err := rdb.Set(c.Request.Context(), kvPrefix+key, *req.Value, ttl).Err(); err != nil {
+		c.Error(apierr.Wrap(err, "redis SET"))
+		return
+	}
+	var ttlOut any
+	if req.TTL > 0 {
+		ttlOut = req.TTL
+	}
+	c.JSON(200, gin.H{"key": key, "value": *req.Value, "ttl_seconds": ttlOut})
+}
+
+// handleKeyGet — GET and TTL in one round trip, from the read client.
+func (a *App) handleKeyGet(c *gin.Context) {
+	rdbRead, err := a.getRedisRead()
+	if err != nil {
+		unavailable(c, "redis", err)
+		return
+	}
+	key, ok := keyParam(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	var get *redis.StringCmd
+	var ttl *redis.DurationCmd
+	_, err = rdbRead.Pipelined(ctx, func(p redis.Pipeliner) error {
+		get = p.Get(ctx, kvPrefix+key)
+		ttl = p.TTL(ctx, kvPrefix+key)
+		return nil
+	})
+	if errors.Is(err, redis.Nil) {
+		c.Error(apierr.NotFound("key not found"))
+		return
+	}
+	if err != nil {
+		c.Error(apierr.Wrap(err, "redis GET"))
+		return
+	}
+	c.JSON(200, gin.H{"key": key, "value": get.Val(), "ttl_seconds": ttlSeconds(ttl.Val())})
+}
+
+// handleKeyExists — EXISTS as HEAD: 200 or 404, no body.
+func (a *App) handleKeyExists(c *gin.Context) {
+	rdbRead, err := a.getRedisRead()
+	if err != nil {
+		unavailable(c, "redis", err)
+		return
+	}
+	key, ok := keyParam(c)
+	if !ok {
+		return
+	}
+	n, err := rdbRead.Exists(c.Request.Context(), kvPrefix+key).Result()
+	if err != nil {
+		c.Error(apierr.Wrap(err, "redis EXISTS"))
+		return
+	}
+	if n == 0 {
+		c.Status(404)
+		return
+	}
+	c.Status(200)
+}
+
+// handleKeyDelete — DEL; 204, or 404 when the key didn't exist.
+func (a *App) handleKeyDelete(c *gin.Context) {
+	rdb, err := a.getRedis()
+	if err != nil {
+		unavailable(c, "redis", err)
+		return
+	}
+	key, ok := keyParam(c)
+	if !ok {
+		return
+	}
+	n, err := rdb.Del(c.Request.Context(), kvPrefix+key).Result()
+	if err != nil {
+		c.Error(apierr.Wrap(err, "redis DEL"))
+		return
+	}
+	if n == 0 {
+		c.Error(apierr.NotFound("key not found"))
+		return
+	}
+	c.Status(204)
+}
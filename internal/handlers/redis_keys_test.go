@@ -0,0 +1,36 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"multi-kind-app/internal/apierr"
+	"multi-kind-app/internal/handlers/handlertest"
+)
+
+func TestKeyRoutesStayInKVNamespace(t *testing.T) {
+	h := handlertest.New(t)
+	h.Redis.Set("session:abc", "secret")
+
+	if w := h.Do(http.MethodPut, "/redis/keys/x", map[string]any{"value": "1"}); w.Code != http.StatusOK {
+		t.Fatalf("put: got %d %s", w.Code, w.Body)
+	}
+	if got, _ := h.Redis.Get("kv:x"); got != "1" {
+		t.Fatalf("kv:x = %q, want 1", got)
+	}
+	if w := h.Do(http.MethodGet, "/redis/keys/x", nil); w.Code != http.StatusOK {
+		t.Fatalf("get: got %d %s", w.Code, w.Body)
+	}
+
+	wantProblem(t, h.Do(http.MethodGet, "/redis/keys/session:abc", nil), http.StatusNotFound, apierr.CodeNotFound)
+	wantProblem(t, h.Do(http.MethodDelete, "/redis/keys/session:abc", nil), http.StatusNotFound, apierr.CodeNotFound)
+	if !h.Redis.Exists("session:abc") {
+		t.Fatal("DELETE /redis/keys/session:abc removed the session key")
+	}
+}
+
+func TestKeyGetWrongTypeIsConflict(t *testing.T) {
+	h := handlertest.New(t)
+	h.Redis.HSet("kv:h", "f", "v")
+	wantProblem(t, h.Do(http.MethodGet, "/redis/keys/h", nil), http.StatusConflict, apierr.CodeConflict)
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ──────────── Backend Registry ────────────
+
+// Backend is one datastore kind. Adding a kind means implementing Backend
+// (and Connector, if it has a client to warm up) and calling Register from
+// an init func; BACKENDS, CRITICAL_BACKENDS, the dependency guard, the
+// health checks and the router all pick it up from there.
+type Backend interface {
+	// Ping is the cheapest round trip the backend supports. Health checks,
+	// the dependency guard and the connect loop all use it.
+	Ping(ctx context.Context) error
+	// Routes registers the backend's own routes.
+	Routes(rt Routes)
+}
+
+// Connector is implemented by backends with a client worth connecting at
+// startup: App.Connect pings them with backoff and calls Connected once,
+// after the first successful ping.
+type Connector interface {
+	Connected(ctx context.Context) error
+}
+
+// Routes is where a Backend registers its routes.
+type Routes struct {
+	Root      *gin.RouterGroup
+	Throttled *gin.RouterGroup // same routes behind the per-key token bucket
+	// Admin is behind ADMIN_API_KEY. It is nil when Routes is called for a
+	// /v{n} group, since admin routes are not versioned.
+	Admin *gin.RouterGroup
+	// Guard answers 503 while the backend is down or switched off; put it
+	// in front of every handler that needs the backend.
+	Guard gin.HandlerFunc
+}
+
+// registry maps a backend name to the constructor of its Backend. It is
+// only written by init funcs, before any App exists.
+var registry = map[string]func(a *App) Backend{}
+
+// Register makes a backend kind available under name. It panics on a
+// duplicate name, like database/sql.Register.
+func Register(name string, newBackend func(a *App) Backend) {
+	if newBackend == nil {
+		panic("handlers: Register backend is nil")
+	}
+	if _, dup := registry[name]; dup {
+		panic(fmt.Sprintf("handlers: Register called twice for backend %q", name))
+	}
+	registry[name] = newBackend
+}
+
+// registeredNames returns every registered backend name, sorted.
+func registeredNames() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"multi-kind-app/internal/apierr"
+)
+
+// ──────────── Redis Secondary Index ────────────
+
+// The index is derived from Mongo, the primary item store:
+//   - idx:items:by-name  hash       name → id
+//   - idx:items:ids      sorted set every id, score 0 so members sort by id
+const (
+	idxByName = "idx:items:by-name"
+	idxIDs    = "idx:items:ids"
+)
+
+// handleReindex — rebuilds both index keys from a full Mongo scan. The new
+// index is written under temporary keys and renamed into place, so lookups
+// never observe a half-built index.
+func (a *App) handleReindex(c *gin.Context) {
+	col, err := a.getItemsCol()
+	if err != nil {
+		unavailable(c, "mongo", err)
+		return
+	}
+	rdb, err := a.getRedis()
+	if err != nil {
+		unavailable(c, "redis", err)
+		return
+	}
+	ctx := c.Request.Context()
+	if err := a.injectedFault("mongo"); err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	cur, err := col.Find(ctx, liveFilter(bson.M{}), options.Find().SetProjection(bson.M{"_id": 1, "name": 1}))
+	if err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	defer cur.Close(ctx)
+
+	tmpByName, tmpIDs := idxByName+":tmp", idxIDs+":tmp"
+	if err := rdb.Del(ctx, tmpByName, tmpIDs).Err(); err != nil {
+		c.Error(apierr.Wrap(err, "redis DEL"))
+		return
+	}
+
+	indexed, named := 0, 0
+	pipe := rdb.Pipeline()
+	for cur.Next(ctx) {
+		var item Item
+		if err := cur.Decode(&item); err != nil {
+			c.Error(apierr.Wrap(err, "mongo decode"))
+			return
+		}
+		if item.Name != "" {
+			pipe.HSet(ctx, tmpByName, item.Name, item.ID)
+			named++
+		}
+		pipe.ZAdd(ctx, tmpIDs, redis.Z{Member: item.ID})
+		if indexed++; indexed%500 == 0 {
+			if _, err := pipe.Exec(ctx); err != nil {
+				c.Error(apierr.Wrap(err, "redis"))
+				return
+			}
+		}
+	}
+	if err := cur.Err(); err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.Error(apierr.Wrap(err, "redis"))
+		return
+	}
+
+	// Both keys are swapped in one MULTI. RENAME overwrites the live key
+	// atomically, but fails on a missing source, so a key whose temporary
+	// was never written (no items, or none with a name) is deleted instead.
+	_, err = rdb.TxPipelined(ctx, func(tx redis.Pipeliner) error {
+		for _, k := range []struct {
+			tmp, live string
+			written   bool
+		}{{tmpByName, idxByName, named > 0}, {tmpIDs, idxIDs, indexed > 0}} {
+			if k.written {
+				tx.Rename(ctx, k.tmp, k.live)
+			} else {
+				tx.Del(ctx, k.live)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.Error(apierr.Wrap(err, "redis RENAME"))
+		return
+	}
+	c.JSON(200, gin.H{"indexed": indexed})
+}
+
+// handleIndexLookup — resolves a name to an id from Redis alone.
+func (a *App) handleIndexLookup(c *gin.Context) {
+	rdbRead, err := a.getRedisRead()
+	if err != nil {
+		unavailable(c, "redis", err)
+		return
+	}
+	name := c.Param("name")
+	id, err := rdbRead.HGet(c.Request.Context(), idxByName, name).Result()
+	if errors.Is(err, redis.Nil) {
+		c.Error(apierr.NotFound("name not indexed"))
+		return
+	}
+	if err != nil {
+		c.Error(apierr.Wrap(err, "redis HGET"))
+		return
+	}
+	c.JSON(200, gin.H{"name": name, "id": id})
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"multi-kind-app/internal/apierr"
+)
+
+// ──────────── Replay-Safe Reference Endpoint ────────────
+
+const (
+	fixedTimestamp = "1970-01-01T00:00:00Z"
+	maskedID       = "<masked>"
+)
+
+// replaySafeWrites insert a fresh record and read back everything the
+// backend stored, including the fields that change on every run.
+func (a *App) replaySafeWrites() map[string]func(ctx context.Context) (map[string]any, error) {
+	return onlyEnabled(a.flags, map[string]func(ctx context.Context) (map[string]any, error){
+		"redis": func(ctx context.Context) (map[string]any, error) {
+			rdb, err := a.getRedis()
+			if err != nil {
+				return nil, err
+			}
+			id, err := rdb.Incr(ctx, "replay-safe:seq").Result()
+			if err != nil {
+				return nil, err
+			}
+			key := "replay-safe:" + strconv.FormatInt(id, 10)
+			if err := rdb.HSet(ctx, key, "id", id, "name", "replay-safe", "created_at", time.Now().UTC()).Err(); err != nil {
+				return nil, err
+			}
+			rdb.Expire(ctx, key, 10*time.Minute)
+			h, err := rdb.HGetAll(ctx, key).Result()
+			if err != nil {
+				return nil, err
+			}
+			out := make(map[string]any, len(h))
+			for k, v := range h {
+				out[k] = v
+			}
+			return out, nil
+		},
+		"mongo": func(ctx context.Context) (map[string]any, error) {
+			mdb, err := a.getMongo()
+			if err != nil {
+				return nil, err
+			}
+			if err := a.injectedFault("mongo"); err != nil {
+				return nil, err
+			}
+			c := mdb.Collection("replay_safe")
+			res, err := c.InsertOne(ctx, bson.M{"name": "replay-safe", "created_at": time.Now().UTC()})
+			if err != nil {
+				return nil, err
+			}
+			var doc bson.M
+			if err := c.FindOne(ctx, bson.M{"_id": res.InsertedID}).Decode(&doc); err != nil {
+				return nil, err
+			}
+			return doc, nil
+		},
+	})
+}
+
+// scrub removes or normalizes every field whose value differs run to run.
+func scrub(rec map[string]any) map[string]any {
+	delete(rec, "_id")
+	if _, ok := rec["id"]; ok {
+		rec["id"] = maskedID
+	}
+	if _, ok := rec["created_at"]; ok {
+		rec["created_at"] = fixedTimestamp
+	}
+	return rec
+}
+
+// handleReplaySafe — the usual insert + read-back, with a response that is
+// byte-identical on every run.
+func (a *App) handleReplaySafe(c *gin.Context) {
+	backend := c.Param("backend")
+	write, ok := a.replaySafeWrites()[backend]
+	if !ok {
+		c.Error(apierr.NotFound("unknown backend: " + backend))
+		return
+	}
+	rec, err := write(c.Request.Context())
+	if err != nil {
+		c.Error(apierr.Wrap(err, backend))
+		return
+	}
+	c.JSON(200, gin.H{"backend": backend, "record": scrub(rec)})
+}
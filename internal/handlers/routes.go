@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"multi-kind-app/internal/apierr"
+	"multi-kind-app/internal/storage"
+)
+
+// Item is the record the item routes read and write.
+type Item = storage.Item
+
+// Router returns a gin engine with every route registered against a.
+// Routes whose backends are switched off by BACKENDS are not registered.
+func (a *App) Router() *gin.Engine {
+	r := gin.New()
+	r.Use(requestID(), a.requestLogger(), recovery(), a.concurrencyLimit(a.cfg.MaxConcurrent))
+	if a.flags.Enabled("mongo") {
+		r.Use(a.snapshotResponses())
+	}
+	if a.cfg.ContentHash {
+		r.Use(contentHash(a.cfg.ContentHashMaxBytes))
+	}
+	// Innermost, so the recorders above see error bodies like any other.
+	r.Use(apierr.Middleware())
+
+	r.GET("/stats", a.handleStats)
+	r.GET("/stats/latency", a.handleLatencyStats)
+
+	admin := r.Group("/admin", a.requireAPIKey())
+	admin.POST("/inject/:backend", a.handleInject)
+	admin.POST("/connections/:backend/reset", a.handleResetPool)
+	admin.POST("/metrics/reset", a.handleMetricsReset)
+	admin.GET("/flags", a.handleFlags)
+	admin.PUT("/flags/:backend", a.handleSetFlag)
+
+	// API routes — unversioned (API-Version header, default v1) and pinned
+	// under each /v{n}. Admin routes are registered once, unversioned.
+	throttle := newThrottle(a.cfg.ThrottleRPS, a.cfg.ThrottleBurst, a.cfg.AdminAPIKey, a.stop).middleware()
+	a.apiRoutes(r.Group("", negotiateVersion()), throttle, admin)
+	for _, v := range apiVersions {
+		a.apiRoutes(r.Group("/v"+strconv.Itoa(v), pinVersion(v)), throttle, nil)
+	}
+
+	// Batch — many sub-requests dispatched through this router in one call
+	r.POST("/batch", handleBatch(r))
+
+	// Diagnostics — which backends return stable output across two reads.
+	// Each covers only the enabled backends.
+	r.GET("/determinism", a.handleDeterminismProbe)
+	r.GET("/ping-all", a.handlePingAll)
+	r.GET("/healthz", a.handleHealthz)
+	r.GET("/readyz", a.handleReadyz)
+	r.GET("/healthz/backend/:name", a.handleBackendHealth)
+	r.GET("/selftest", a.handleSelfTest)
+	r.POST("/diff", a.handleDiff)
+	r.GET("/replay-safe/:backend", a.handleReplaySafe)
+	r.GET("/fingerprint", a.handleFingerprint)
+	r.GET("/trace/*path", handleTrace(r))
+
+	return r
+}
+
+// apiRoutes registers the versioned routes on g. admin is nil for the
+// /v{n} groups.
+func (a *App) apiRoutes(g *gin.RouterGroup, throttle gin.HandlerFunc, admin *gin.RouterGroup) {
+	// Single-kind routes — each enabled backend registers its own, behind a
+	// guard that answers 503 while it is down or switched off
+	throttled := g.Group("/throttled", throttle)
+	for _, name := range a.backendNames {
+		if a.flags.Enabled(name) {
+			a.backends[name].Routes(Routes{Root: g, Throttled: throttled, Admin: admin, Guard: a.requires(name)})
+		}
+	}
+
+	// Multi-DB routes — test multi-kind
+	if a.flags.Enabled("redis", "mongo") {
+		itemsUp := a.requires(a.itemDeps()...)
+		bothUp := a.requires("redis", "mongo")
+		g.POST("/api/item", itemsUp, a.createItem)               // Mongo + Redis
+		g.GET("/api/item/:id", itemsUp, a.getItem)               // Mongo + Redis
+		g.PUT("/api/item/:id", itemsUp, a.putItem)               // Mongo + Redis, full replace
+		g.GET("/api/items/summary", a.handleSummary)             // Mongo + Redis, concurrent
+		g.POST("/api/item/:id/promote", bothUp, a.handlePromote) // Mongo → Redis hot tier
+		g.POST("/api/items/reindex", bothUp, a.handleReindex)    // Mongo → Redis index
+	}
+
+	// Repository-backed items — same handlers over every enabled
+	// ItemRepository
+	stores := g.Group("/stores/:store/items")
+	stores.POST("", a.handleRepoCreate)
+	stores.GET("", a.handleRepoList)
+	stores.GET("/latest", a.handleRepoLatest)
+	stores.DELETE("/:id", a.handleRepoDelete) // soft delete
+	stores.POST("/:id/restore", a.handleRepoRestore)
+	g.POST("/items/bulk", a.handleBulkCreate)           // ?store=, InsertMany / pipelined
+	g.DELETE("/items", a.handleBulkDelete)              // ?store=&older_than=, DeleteMany
+	g.PUT("/items/by-name/:name", a.handleUpsertByName) // ?store=, upsert
+	g.POST("/fanout", a.handleFanout)                   // every store, per-store results
+
+	// Outbound POST — Mongo audit write + webhook call
+	if a.flags.Enabled("http", "mongo") {
+		httpMongoUp := a.requires("http", "mongo")
+		g.POST("/webhook/trigger", httpMongoUp, a.handleWebhook)
+		g.GET("/compose", httpMongoUp, a.handleCompose) // HTTP → HTTP → Mongo
+	}
+}
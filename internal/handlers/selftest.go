@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ──────────── Self Test ────────────
+
+type selfTestResult struct {
+	Pass      bool    `json:"pass"`
+	Detail    string  `json:"detail"`
+	LatencyMs float64 `json:"latency_ms"`
+}
+
+// selfTests run a write → read → verify → delete cycle per backend. The
+// upstream HTTP API is read-only, so its check is a GET that must decode.
+func (a *App) selfTests() map[string]func(ctx context.Context, token string) error {
+	return onlyEnabled(a.flags, map[string]func(ctx context.Context, token string) error{
+		"redis": func(ctx context.Context, token string) error {
+			rdb, err := a.getRedis()
+			if err != nil {
+				return err
+			}
+			key := "selftest:" + token
+			defer rdb.Del(context.WithoutCancel(ctx), key)
+			if err := rdb.Set(ctx, key, token, time.Minute).Err(); err != nil {
+				return fmt.Errorf("SET: %w", err)
+			}
+			got, err := rdb.Get(ctx, key).Result()
+			if err != nil {
+				return fmt.Errorf("GET: %w", err)
+			}
+			if got != token {
+				return fmt.Errorf("GET returned %q, want %q", got, token)
+			}
+			return rdb.Del(ctx, key).Err()
+		},
+		"mongo": func(ctx context.Context, token string) error {
+			mdb, err := a.getMongo()
+			if err != nil {
+				return err
+			}
+			if err := a.injectedFault("mongo"); err != nil {
+				return err
+			}
+			st := mdb.Collection("selftest")
+			defer st.DeleteOne(context.WithoutCancel(ctx), bson.M{"_id": token})
+			if _, err := st.InsertOne(ctx, bson.M{"_id": token, "value": token}); err != nil {
+				return fmt.Errorf("insert: %w", err)
+			}
+			var doc struct {
+				Value string `bson:"value"`
+			}
+			if err := st.FindOne(ctx, bson.M{"_id": token}).Decode(&doc); err != nil {
+				return fmt.Errorf("find: %w", err)
+			}
+			if doc.Value != token {
+				return fmt.Errorf("find returned %q, want %q", doc.Value, token)
+			}
+			_, err = st.DeleteOne(ctx, bson.M{"_id": token})
+			return err
+		},
+		"http": func(ctx context.Context, _ string) error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.cfg.UpstreamURL, nil)
+			if err != nil {
+				return err
+			}
+			resp, err := a.httpClient.Do(req)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != 200 {
+				return fmt.Errorf("upstream status %d", resp.StatusCode)
+			}
+			var v any
+			if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+				return fmt.Errorf("upstream body: %w", err)
+			}
+			return nil
+		},
+	})
+}
+
+// handleSelfTest — runs every self test concurrently with a fresh token.
+// 200 only if all pass; 503 with the per-backend report otherwise.
+func (a *App) handleSelfTest(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	token := randomHex(8)
+
+	tests := a.selfTests()
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		out = make(map[string]selfTestResult, len(tests))
+	)
+	for name, fn := range tests {
+		wg.Add(1)
+		go func(name string, fn func(context.Context, string) error) {
+			defer wg.Done()
+			start := time.Now()
+			err := fn(ctx, token)
+			res := selfTestResult{Pass: err == nil, Detail: "ok", LatencyMs: float64(time.Since(start).Microseconds()) / 1000}
+			if err != nil {
+				res.Detail = err.Error()
+			}
+			mu.Lock()
+			out[name] = res
+			mu.Unlock()
+		}(name, fn)
+	}
+	wg.Wait()
+
+	pass := true
+	for _, res := range out {
+		pass = pass && res.Pass
+	}
+	code := 200
+	if !pass {
+		code = 503
+	}
+	c.JSON(code, gin.H{"pass": pass, "token": token, "backends": out})
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	"multi-kind-app/internal/apierr"
+)
+
+// ──────────── Session Handlers (Redis) ────────────
+
+const sessionPrefix = "session:"
+
+// handleSessionCreate — stores the request body as a session blob with a TTL.
+func (a *App) handleSessionCreate(c *gin.Context) {
+	rdb, err := a.getRedis()
+	if err != nil {
+		unavailable(c, "redis", err)
+		return
+	}
+	var data map[string]any
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.Error(apierr.BadRequest(err.Error()))
+		return
+	}
+	blob, err := json.Marshal(data)
+	if err != nil {
+		c.Error(apierr.BadRequest(err.Error()))
+		return
+	}
+	id := randomHex(16)
+	if err := rdb.Set(c.Request.Context(), sessionPrefix+id, blob, time.Duration(a.cfg.SessionTTL)).Err(); err != nil {
+		c.Error(apierr.Wrap(err, "redis SET"))
+		return
+	}
+	c.JSON(201, gin.H{"id": id, "ttl_seconds": int(time.Duration(a.cfg.SessionTTL).Seconds())})
+}
+
+// handleSessionGet — reads a session back; expired or unknown ids are 404.
+func (a *App) handleSessionGet(c *gin.Context) {
+	rdbRead, err := a.getRedisRead()
+	if err != nil {
+		unavailable(c, "redis", err)
+		return
+	}
+	id := c.Param("id")
+	ctx := c.Request.Context()
+
+	blob, err := rdbRead.Get(ctx, sessionPrefix+id).Bytes()
+	if errors.Is(err, redis.Nil) {
+		c.Error(apierr.NotFound("session not found"))
+		return
+	}
+	if err != nil {
+		c.Error(apierr.Wrap(err, "redis GET"))
+		return
+	}
+	ttl, _ := rdbRead.TTL(ctx, sessionPrefix+id).Result()
+	c.JSON(200, gin.H{"id": id, "data": json.RawMessage(blob), "ttl_seconds": int(ttl.Seconds())})
+}
+
+// handleSessionDelete — removes a session; deleting an unknown id is 404.
+func (a *App) handleSessionDelete(c *gin.Context) {
+	rdb, err := a.getRedis()
+	if err != nil {
+		unavailable(c, "redis", err)
+		return
+	}
+	n, err := rdb.Del(c.Request.Context(), sessionPrefix+c.Param("id")).Result()
+	if err != nil {
+		c.Error(apierr.Wrap(err, "redis DEL"))
+		return
+	}
+	if n == 0 {
+		c.Error(apierr.NotFound("session not found"))
+		return
+	}
+	c.Status(204)
+}
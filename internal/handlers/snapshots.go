@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"multi-kind-app/internal/apierr"
+)
+
+// ──────────── Response Snapshots ────────────
+
+// bodyWriter tees everything written to the client into buf.
+type bodyWriter struct {
+	gin.ResponseWriter
+	buf bytes.Buffer
+}
+
+func (w *bodyWriter) Write(b []byte) (int, error) {
+	w.buf.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyWriter) WriteString(s string) (int, error) {
+	w.buf.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// snapshotsPerRoute is how many snapshots each route keeps; it is also the
+// most handleSnapshots returns.
+const snapshotsPerRoute = 20
+
+// snapshotResponses stores the response of every configured route in the
+// snapshots collection and trims that route to its newest
+// snapshotsPerRoute. The collection is also capped by size, which bounds
+// the total however many routes are configured. The writes happen off the
+// request path.
+func (a *App) snapshotResponses() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := unversionedRoute(c.FullPath())
+		if !a.snapshotRoutes[route] {
+			c.Next()
+			return
+		}
+		bw := &bodyWriter{ResponseWriter: c.Writer}
+		c.Writer = bw
+		c.Next()
+
+		doc := bson.M{
+			"route":      route,
+			"path":       c.Request.URL.Path,
+			"status":     bw.Status(),
+			"body":       bw.buf.String(),
+			"request_id": c.GetString("request_id"),
+			"at":         time.Now().UTC(),
+		}
+		ctx := context.WithoutCancel(c.Request.Context())
+		go func() {
+			ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+			mdb, err := a.getMongo()
+			if err == nil {
+				_, err = mdb.Collection("snapshots").InsertOne(ctx, doc)
+			}
+			if err == nil {
+				err = trimSnapshots(ctx, mdb.Collection("snapshots"), route)
+			}
+			if err != nil {
+				ctxLog(ctx).Warn("snapshot insert failed", "route", route, "err", err)
+			}
+		}()
+	}
+}
+
+// trimSnapshots deletes all but the newest snapshotsPerRoute snapshots of
+// route. Inserted _ids are ObjectIDs, so they order by insertion.
+func trimSnapshots(ctx context.Context, col *mongo.Collection, route string) error {
+	var oldest bson.M
+	opts := options.FindOne().SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetSkip(snapshotsPerRoute).SetProjection(bson.M{"_id": 1})
+	err := col.FindOne(ctx, bson.M{"route": route}, opts).Decode(&oldest)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, err = col.DeleteMany(ctx, bson.M{"route": route, "_id": bson.M{"$lte": oldest["_id"]}})
+	return err
+}
+
+// handleSnapshots — most recent snapshots for a route pattern, newest first.
+// The route is the wildcard tail, e.g. GET /snapshots/api/item/:id.
+func (a *App) handleSnapshots(c *gin.Context) {
+	mdb, err := a.getMongo()
+	if err != nil {
+		unavailable(c, "mongo", err)
+		return
+	}
+	route := c.Param("route")
+	opts := options.Find().SetSort(bson.D{{Key: "$natural", Value: -1}}).SetLimit(snapshotsPerRoute).
+		SetProjection(bson.M{"_id": 0})
+	cur, err := mdb.Collection("snapshots").Find(c.Request.Context(), bson.M{"route": route}, opts)
+	if err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	snaps := []bson.M{}
+	if err := cur.All(c.Request.Context(), &snaps); err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	c.JSON(200, gin.H{"route": route, "snapshots": snaps})
+}
@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"multi-kind-app/internal/apierr"
+	"multi-kind-app/internal/storage"
+)
+
+// ──────────── Repository-Backed Items ────────────
+
+// storeParam resolves :store, writing a 404 for unknown stores and a 503
+// when the store's backend is down.
+func (a *App) storeParam(c *gin.Context) (string, bool) {
+	store := c.Param("store")
+	if !a.items.Has(store) {
+		c.Error(apierr.NotFound("unknown store: " + store))
+		return "", false
+	}
+	return store, a.guard(c, store)
+}
+
+// storeQuery is storeParam for the bulk routes, which take ?store=.
+func (a *App) storeQuery(c *gin.Context) (string, bool) {
+	store := c.Query("store")
+	if !a.items.Has(store) {
+		c.Error(apierr.BadRequest("store must be one of: " + strings.Join(a.items.Stores(), ", ")))
+		return "", false
+	}
+	return store, a.guard(c, store)
+}
+
+// itemEnvelope is the single-item body: {"item": ...} in v1, the bare
+// item from v2 on.
+func itemEnvelope(c *gin.Context, item Item) any {
+	if apiVersion(c) >= 2 {
+		return item
+	}
+	return gin.H{"item": item}
+}
+
+// handleRepoCreate — 201 with the stored item; the id is generated when
+// the body omits it.
+func (a *App) handleRepoCreate(c *gin.Context) {
+	store, ok := a.storeParam(c)
+	if !ok {
+		return
+	}
+	var item Item
+	if err := c.ShouldBindJSON(&item); err != nil {
+		c.Error(apierr.BadRequest(err.Error()))
+		return
+	}
+	created, err := a.items.Create(c.Request.Context(), store, item)
+	if err != nil {
+		c.Error(apierr.Wrap(err, store))
+		return
+	}
+	c.JSON(201, itemEnvelope(c, created))
+}
+
+// handleRepoLatest — the most recently created item.
+func (a *App) handleRepoLatest(c *gin.Context) {
+	store, ok := a.storeParam(c)
+	if !ok {
+		return
+	}
+	item, err := a.items.Latest(c.Request.Context(), store)
+	if err != nil {
+		c.Error(apierr.Wrap(err, store))
+		return
+	}
+	c.JSON(200, itemEnvelope(c, item))
+}
+
+// handleRepoList — one page, newest first unless ?sort= says otherwise,
+// with the total and next_offset when more items remain.
+func (a *App) handleRepoList(c *gin.Context) {
+	store, ok := a.storeParam(c)
+	if !ok {
+		return
+	}
+	q, err := a.listQuery(c, storage.SortNewest)
+	if err != nil {
+		c.Error(apierr.BadRequest(err.Error()))
+		return
+	}
+	page, err := a.items.List(c.Request.Context(), store, q)
+	if err != nil {
+		c.Error(apierr.Wrap(err, store))
+		return
+	}
+	c.JSON(200, page)
+}
+
+// handleRepoDelete — 204 on success. The item is only soft-deleted: lists
+// leave it out unless ?include_deleted=true, and restore brings it back.
+func (a *App) handleRepoDelete(c *gin.Context) {
+	store, ok := a.storeParam(c)
+	if !ok {
+		return
+	}
+	if err := a.items.Delete(c.Request.Context(), store, c.Param("id")); err != nil {
+		c.Error(apierr.Wrap(err, store))
+		return
+	}
+	// The mongo store shares its collection, and its cache entries, with
+	// GET /api/item/:id.
+	a.cache.Invalidate(store, c.Param("id"))
+	c.Status(204)
+}
+
+// handleRepoRestore — undeletes a soft-deleted item and returns it; 409
+// when the item isn't deleted.
+func (a *App) handleRepoRestore(c *gin.Context) {
+	store, ok := a.storeParam(c)
+	if !ok {
+		return
+	}
+	item, err := a.items.Restore(c.Request.Context(), store, c.Param("id"))
+	if err != nil {
+		c.Error(apierr.Wrap(err, store))
+		return
+	}
+	a.cache.Invalidate(store, item.ID)
+	c.JSON(200, itemEnvelope(c, item))
+}
+
+// handleUpsertByName — sets the value of the item named :name in ?store=,
+// creating the item when there is none: 201 if it was created, 200 if an
+// existing one was updated.
+func (a *App) handleUpsertByName(c *gin.Context) {
+	store, ok := a.storeQuery(c)
+	if !ok {
+		return
+	}
+	var body struct {
+		Value string `json:"value"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.Error(apierr.BadRequest(err.Error()))
+		return
+	}
+	item, created, err := a.items.UpsertByName(c.Request.Context(), store, c.Param("name"), body.Value)
+	if err != nil {
+		c.Error(apierr.Wrap(err, store))
+		return
+	}
+	a.cache.Invalidate(store, item.ID)
+	status := 200
+	if created {
+		status = 201
+	}
+	c.JSON(status, gin.H{"created": created, "item": item})
+}
+
+// bulkResult is one entry of the bulk insert response.
+type bulkResult struct {
+	Index  int         `json:"index"`
+	ID     string      `json:"id"`
+	Status int         `json:"status"`
+	Code   apierr.Code `json:"code,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// handleBulkCreate — inserts up to MaxBulkItems items into ?store= in as
+// few round trips as the store allows. One bad item never fails the
+// others: each gets its own status in results.
+func (a *App) handleBulkCreate(c *gin.Context) {
+	store, ok := a.storeQuery(c)
+	if !ok {
+		return
+	}
+	var items []Item
+	if err := c.ShouldBindJSON(&items); err != nil {
+		c.Error(apierr.BadRequest("body must be a JSON array of items: " + err.Error()))
+		return
+	}
+	if len(items) == 0 || len(items) > a.cfg.MaxBulkItems {
+		c.Error(apierr.BadRequest(fmt.Sprintf("send between 1 and %d items", a.cfg.MaxBulkItems)))
+		return
+	}
+	results, err := a.items.CreateMany(c.Request.Context(), store, items)
+	if err != nil {
+		c.Error(apierr.Wrap(err, store))
+		return
+	}
+
+	out := make([]bulkResult, len(results))
+	created := 0
+	for i, r := range results {
+		out[i] = bulkResult{Index: i, ID: r.Item.ID, Status: 201}
+		if r.Err != nil {
+			e := apierr.From(r.Err)
+			out[i].Status, out[i].Code, out[i].Error = e.Status, e.Code, e.Detail
+			continue
+		}
+		created++
+	}
+	c.JSON(200, gin.H{"store": store, "created": created, "failed": len(out) - created, "results": out})
+}
+
+// handleBulkDelete — soft-deletes every item in ?store= created before
+// ?older_than=, which is a duration back from now (e.g. 72h) or an RFC 3339
+// time. Answers with the deleted count.
+func (a *App) handleBulkDelete(c *gin.Context) {
+	store, ok := a.storeQuery(c)
+	if !ok {
+		return
+	}
+	olderThan := c.Query("older_than")
+	cutoff, err := time.Parse(time.RFC3339Nano, olderThan)
+	if err != nil {
+		d, derr := time.ParseDuration(olderThan)
+		if derr != nil || d <= 0 {
+			c.Error(apierr.BadRequest("older_than must be a positive duration (e.g. 72h) or an RFC 3339 time"))
+			return
+		}
+		cutoff = time.Now().Add(-d)
+	}
+	deleted, err := a.items.DeleteMany(c.Request.Context(), store, storage.ListQuery{CreatedBefore: cutoff})
+	if deleted > 0 {
+		a.cache.InvalidateBackend(store)
+	}
+	if err != nil {
+		c.Error(apierr.Wrap(err, store).With("deleted", deleted))
+		return
+	}
+	c.JSON(200, gin.H{"store": store, "deleted": deleted, "older_than": cutoff.UTC()})
+}
@@ -0,0 +1,308 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"multi-kind-app/internal/apierr"
+	"multi-kind-app/internal/config"
+	"multi-kind-app/internal/handlers/handlertest"
+	"multi-kind-app/internal/storage"
+)
+
+type itemBody struct {
+	Item storage.Item `json:"item"`
+}
+
+type problemBody struct {
+	Status int         `json:"status"`
+	Code   apierr.Code `json:"code"`
+	Detail string      `json:"detail"`
+}
+
+// wantProblem fails unless w is a problem+json response with status and code.
+func wantProblem(t *testing.T, w *httptest.ResponseRecorder, status int, code apierr.Code) {
+	t.Helper()
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, apierr.ContentType) {
+		t.Fatalf("content type %q, want %s", ct, apierr.ContentType)
+	}
+	p := handlertest.Decode[problemBody](t, w)
+	if w.Code != status || p.Status != status || p.Code != code {
+		t.Fatalf("got %d %s, want %d %s", w.Code, w.Body, status, code)
+	}
+}
+
+type listBody struct {
+	Items      []storage.Item `json:"items"`
+	Total      int64          `json:"total"`
+	NextOffset *int64         `json:"next_offset"`
+}
+
+func TestRepoRoutes(t *testing.T) {
+	for _, store := range []string{"mongo", "redis"} {
+		t.Run(store, func(t *testing.T) {
+			h := handlertest.New(t)
+			base := "/stores/" + store + "/items"
+
+			w := h.Do(http.MethodGet, base+"/latest", nil)
+			if w.Code != http.StatusNotFound {
+				t.Fatalf("latest on empty store: got %d, want 404", w.Code)
+			}
+
+			for _, id := range []string{"a", "b", "c"} {
+				w := h.Do(http.MethodPost, base, storage.Item{ID: id, Name: "item " + id, Value: id})
+				if w.Code != http.StatusCreated {
+					t.Fatalf("create %s: got %d: %s", id, w.Code, w.Body)
+				}
+				if got := handlertest.Decode[itemBody](t, w).Item; got.ID != id || got.CreatedAt == nil {
+					t.Fatalf("create %s: got %+v", id, got)
+				}
+			}
+
+			w = h.Do(http.MethodPost, base, storage.Item{ID: "a", Name: "again"})
+			wantProblem(t, w, http.StatusConflict, apierr.CodeConflict)
+
+			w = h.Do(http.MethodPost, base, storage.Item{Name: "bad\x00name"})
+			wantProblem(t, w, http.StatusUnprocessableEntity, apierr.CodeInvalid)
+
+			w = h.Do(http.MethodGet, base+"?limit=2", nil)
+			page := handlertest.Decode[listBody](t, w)
+			if len(page.Items) != 2 || page.Total != 3 || page.NextOffset == nil || *page.NextOffset != 2 {
+				t.Fatalf("first page: got %s", w.Body)
+			}
+
+			w = h.Do(http.MethodGet, base+"?sort=created_at&limit=1", nil)
+			if page := handlertest.Decode[listBody](t, w); len(page.Items) != 1 || page.Items[0].ID != "a" {
+				t.Fatalf("oldest first: got %s", w.Body)
+			}
+			wantProblem(t, h.Do(http.MethodGet, base+"?sort=bogus", nil), http.StatusBadRequest, apierr.CodeBadRequest)
+
+			w = h.Do(http.MethodGet, base+"?name=item+b", nil)
+			if page := handlertest.Decode[listBody](t, w); page.Total != 1 || page.Items[0].ID != "b" {
+				t.Fatalf("name filter: got %s", w.Body)
+			}
+			w = h.Do(http.MethodGet, base+"?created_before=2000-01-01T00:00:00Z", nil)
+			if page := handlertest.Decode[listBody](t, w); page.Total != 0 || len(page.Items) != 0 {
+				t.Fatalf("created_before filter: got %s", w.Body)
+			}
+			wantProblem(t, h.Do(http.MethodGet, base+"?created_after=yesterday", nil), http.StatusBadRequest, apierr.CodeBadRequest)
+
+			w = h.Do(http.MethodDelete, base+"/b", nil)
+			if w.Code != http.StatusNoContent {
+				t.Fatalf("delete: got %d", w.Code)
+			}
+			w = h.Do(http.MethodDelete, base+"/b", nil)
+			if w.Code != http.StatusNotFound {
+				t.Fatalf("second delete: got %d, want 404", w.Code)
+			}
+
+			items, _ := h.Repos[store].List(context.Background(), storage.ListQuery{})
+			if len(items) != 2 {
+				t.Fatalf("store holds %d items, want 2", len(items))
+			}
+			w = h.Do(http.MethodGet, base+"?include_deleted=true", nil)
+			if page := handlertest.Decode[listBody](t, w); page.Total != 3 {
+				t.Fatalf("include_deleted: got %s", w.Body)
+			}
+			wantProblem(t, h.Do(http.MethodPost, base, storage.Item{ID: "b"}), http.StatusConflict, apierr.CodeConflict)
+
+			w = h.Do(http.MethodPost, base+"/b/restore", nil)
+			if got := handlertest.Decode[itemBody](t, w).Item; w.Code != http.StatusOK || got.ID != "b" || got.DeletedAt != nil {
+				t.Fatalf("restore: got %d %s", w.Code, w.Body)
+			}
+			wantProblem(t, h.Do(http.MethodPost, base+"/b/restore", nil), http.StatusConflict, apierr.CodeConflict)
+			wantProblem(t, h.Do(http.MethodPost, base+"/zz/restore", nil), http.StatusNotFound, apierr.CodeNotFound)
+		})
+	}
+}
+
+func TestRepoRoutesUnknownStore(t *testing.T) {
+	h := handlertest.New(t)
+	wantProblem(t, h.Do(http.MethodGet, "/stores/nope/items", nil), http.StatusNotFound, apierr.CodeNotFound)
+}
+
+func TestHTTPOnlyUsesStub(t *testing.T) {
+	h := handlertest.New(t)
+	h.HTTP.Set(http.MethodGet, handlertest.UpstreamURL, 200, `{"id":1}`)
+
+	w := h.Do(http.MethodGet, "/http", nil)
+	if w.Code != http.StatusOK || w.Body.String() != `{"id":1}` {
+		t.Fatalf("got %d %s", w.Code, w.Body)
+	}
+	if got := h.HTTP.Requests(); len(got) != 1 {
+		t.Fatalf("upstream saw %v, want one GET", got)
+	}
+}
+
+func TestHTTPOnlyUpstreamDown(t *testing.T) {
+	h := handlertest.New(t)
+	wantProblem(t, h.Do(http.MethodGet, "/http", nil), http.StatusBadGateway, apierr.CodeUpstream)
+}
+
+func TestRepoRoutesVersions(t *testing.T) {
+	h := handlertest.New(t)
+	h.Do(http.MethodPost, "/stores/mongo/items", storage.Item{ID: "a", Name: "apple"})
+
+	for _, tt := range []struct {
+		path, header, want string
+	}{
+		{"/stores/mongo/items/latest", "", "1"},
+		{"/v1/stores/mongo/items/latest", "", "1"},
+		{"/v2/stores/mongo/items/latest", "", "2"},
+		{"/stores/mongo/items/latest", "v2", "2"},
+		{"/v1/stores/mongo/items/latest", "2", "1"},
+	} {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.header != "" {
+			req.Header.Set("API-Version", tt.header)
+		}
+		w := httptest.NewRecorder()
+		h.Router.ServeHTTP(w, req)
+		if got := w.Header().Get("API-Version"); w.Code != http.StatusOK || got != tt.want {
+			t.Fatalf("%s (API-Version %q): got %d v%s, want v%s", tt.path, tt.header, w.Code, got, tt.want)
+		}
+		var item storage.Item
+		if tt.want == "1" {
+			item = handlertest.Decode[itemBody](t, w).Item
+		} else {
+			item = handlertest.Decode[storage.Item](t, w)
+		}
+		if item.ID != "a" {
+			t.Fatalf("%s: v%s body %s", tt.path, tt.want, w.Body)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/stores/mongo/items/latest", nil)
+	req.Header.Set("API-Version", "9")
+	w := httptest.NewRecorder()
+	h.Router.ServeHTTP(w, req)
+	wantProblem(t, w, http.StatusBadRequest, apierr.CodeBadRequest)
+}
+
+func TestBulkCreate(t *testing.T) {
+	h := handlertest.New(t)
+	items := []storage.Item{{ID: "a", Name: "one"}, {ID: "a", Name: "again"}, {Name: "bad\x00"}, {Name: "minted"}}
+	w := h.Do(http.MethodPost, "/items/bulk?store=redis", items)
+	body := handlertest.Decode[struct {
+		Created int `json:"created"`
+		Results []struct {
+			ID     string      `json:"id"`
+			Status int         `json:"status"`
+			Code   apierr.Code `json:"code"`
+		} `json:"results"`
+	}](t, w)
+	if w.Code != http.StatusOK || body.Created != 2 || len(body.Results) != 4 {
+		t.Fatalf("got %d %s", w.Code, w.Body)
+	}
+	want := []int{201, 409, 422, 201}
+	for i, r := range body.Results {
+		if r.Status != want[i] {
+			t.Errorf("result %d: got %d %s, want %d", i, r.Status, r.Code, want[i])
+		}
+	}
+	if body.Results[3].ID == "" {
+		t.Error("no id minted for the item without one")
+	}
+
+	wantProblem(t, h.Do(http.MethodPost, "/items/bulk?store=pg", items), http.StatusBadRequest, apierr.CodeBadRequest)
+	wantProblem(t, h.Do(http.MethodPost, "/items/bulk?store=redis", []storage.Item{}), http.StatusBadRequest, apierr.CodeBadRequest)
+}
+
+func TestBulkDelete(t *testing.T) {
+	h := handlertest.New(t)
+	ctx := context.Background()
+	h.Repos["mongo"].Create(ctx, storage.Item{ID: "a"})
+
+	w := h.Do(http.MethodDelete, "/items?store=mongo&older_than=1h", nil)
+	if body := handlertest.Decode[struct{ Deleted int64 }](t, w); w.Code != http.StatusOK || body.Deleted != 0 {
+		t.Fatalf("nothing old enough: got %d %s", w.Code, w.Body)
+	}
+	future := time.Now().UTC().Add(time.Minute).Format(time.RFC3339)
+	w = h.Do(http.MethodDelete, "/items?store=mongo&older_than="+future, nil)
+	if body := handlertest.Decode[struct{ Deleted int64 }](t, w); body.Deleted != 1 {
+		t.Fatalf("cutoff in the future: got %s", w.Body)
+	}
+	wantProblem(t, h.Do(http.MethodDelete, "/items?store=mongo", nil), http.StatusBadRequest, apierr.CodeBadRequest)
+}
+
+func TestUpsertByName(t *testing.T) {
+	h := handlertest.New(t)
+	type upsertBody struct {
+		Created bool         `json:"created"`
+		Item    storage.Item `json:"item"`
+	}
+
+	w := h.Do(http.MethodPut, "/items/by-name/apple?store=mongo", map[string]string{"value": "red"})
+	first := handlertest.Decode[upsertBody](t, w)
+	if w.Code != http.StatusCreated || !first.Created || first.Item.Value != "red" {
+		t.Fatalf("create: got %d %s", w.Code, w.Body)
+	}
+	w = h.Do(http.MethodPut, "/items/by-name/apple?store=mongo", map[string]string{"value": "green"})
+	second := handlertest.Decode[upsertBody](t, w)
+	if w.Code != http.StatusOK || second.Created || second.Item.ID != first.Item.ID || second.Item.Value != "green" {
+		t.Fatalf("update: got %d %s", w.Code, w.Body)
+	}
+	if n, _ := h.Repos["mongo"].Count(context.Background(), storage.ListQuery{}); n != 1 {
+		t.Fatalf("store holds %d items, want 1", n)
+	}
+	wantProblem(t, h.Do(http.MethodPut, "/items/by-name/apple?store=nope", map[string]string{}), http.StatusBadRequest, apierr.CodeBadRequest)
+}
+
+type fanoutBody struct {
+	ID      string `json:"id"`
+	Created int    `json:"created"`
+	Failed  int    `json:"failed"`
+	Skipped int    `json:"skipped"`
+	Stores  map[string]struct {
+		OK      bool        `json:"ok"`
+		Skipped bool        `json:"skipped"`
+		Status  int         `json:"status"`
+		Code    apierr.Code `json:"code"`
+	} `json:"stores"`
+}
+
+func TestFanout(t *testing.T) {
+	h := handlertest.New(t)
+	h.Repos["redis"].Create(context.Background(), storage.Item{ID: "x"})
+
+	w := h.Do(http.MethodPost, "/fanout", storage.Item{ID: "x", Name: "apple"})
+	body := handlertest.Decode[fanoutBody](t, w)
+	if w.Code != http.StatusOK || body.ID != "x" || body.Created != 1 || body.Failed != 1 {
+		t.Fatalf("fanout: got %d %s", w.Code, w.Body)
+	}
+	if got := body.Stores["mongo"]; !got.OK || got.Status != http.StatusCreated {
+		t.Fatalf("mongo: got %+v", got)
+	}
+	if got := body.Stores["redis"]; got.OK || got.Status != http.StatusConflict || got.Code != apierr.CodeConflict {
+		t.Fatalf("redis: got %+v", got)
+	}
+	wantProblem(t, h.Do(http.MethodPost, "/fanout", storage.Item{Name: "a\x00b"}), http.StatusUnprocessableEntity, apierr.CodeInvalid)
+}
+
+func TestFanoutSkipsDisabledStores(t *testing.T) {
+	h := handlertest.New(t, func(c *config.Config) { c.AdminAPIKey = "k" })
+	req := httptest.NewRequest(http.MethodPut, "/admin/flags/redis", strings.NewReader(`{"enabled": false}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", "k")
+	w := httptest.NewRecorder()
+	h.Router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("disable redis: got %d %s", w.Code, w.Body)
+	}
+
+	w = h.Do(http.MethodPost, "/fanout", storage.Item{ID: "x", Name: "apple"})
+	body := handlertest.Decode[fanoutBody](t, w)
+	if w.Code != http.StatusOK || body.Created != 1 || body.Failed != 0 || body.Skipped != 1 {
+		t.Fatalf("fanout: got %d %s", w.Code, w.Body)
+	}
+	if got := body.Stores["redis"]; got.OK || !got.Skipped || got.Status != http.StatusServiceUnavailable || got.Code != apierr.CodeUnavailable {
+		t.Fatalf("redis: got %+v", got)
+	}
+	if items, _ := h.Repos["redis"].List(context.Background(), storage.ListQuery{Limit: 10}); len(items) != 0 {
+		t.Fatalf("disabled store was written: %+v", items)
+	}
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ──────────── Item Summary ────────────
+
+type backendSummary struct {
+	Count  int64  `json:"count"`
+	Latest any    `json:"latest"`
+	Error  string `json:"error,omitempty"`
+}
+
+// itemSummaries count each backend's items and find the most recent one.
+func (a *App) itemSummaries() map[string]func(ctx context.Context) (backendSummary, error) {
+	return onlyEnabled(a.flags, map[string]func(ctx context.Context) (backendSummary, error){
+		// Mongo: natural order descending is insertion order for the items
+		// collection, so its first document is the newest.
+		"mongo": func(ctx context.Context) (backendSummary, error) {
+			col, err := a.getItemsCol()
+			if err != nil {
+				return backendSummary{}, err
+			}
+			if err := a.injectedFault("mongo"); err != nil {
+				return backendSummary{}, err
+			}
+			n, err := col.CountDocuments(ctx, liveFilter(bson.M{}))
+			if err != nil {
+				return backendSummary{}, err
+			}
+			var item Item
+			opts := options.FindOne().SetSort(bson.D{{Key: "$natural", Value: -1}})
+			err = col.FindOne(ctx, liveFilter(bson.M{}), opts).Decode(&item)
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return backendSummary{Count: n}, nil
+			}
+			if err != nil {
+				return backendSummary{Count: n}, err
+			}
+			return backendSummary{Count: n, Latest: item}, nil
+		},
+		// Redis: every item:* key is written with the same TTL, so the key with
+		// the most time left is the one written last.
+		"redis": func(ctx context.Context) (backendSummary, error) {
+			rdb, err := a.getRedisRead()
+			if err != nil {
+				return backendSummary{}, err
+			}
+			var (
+				out     backendSummary
+				cursor  uint64
+				newest  string
+				longest time.Duration = -1
+			)
+			for {
+				keys, next, err := rdb.Scan(ctx, cursor, "item:*", 500).Result()
+				if err != nil {
+					return out, err
+				}
+				out.Count += int64(len(keys))
+				if len(keys) > 0 {
+					pipe := rdb.Pipeline()
+					ttls := make([]*redis.DurationCmd, len(keys))
+					for i, k := range keys {
+						ttls[i] = pipe.PTTL(ctx, k)
+					}
+					if _, err := pipe.Exec(ctx); err != nil {
+						return out, err
+					}
+					for i, cmd := range ttls {
+						if d := cmd.Val(); d > longest {
+							longest, newest = d, keys[i]
+						}
+					}
+				}
+				if cursor = next; cursor == 0 {
+					break
+				}
+			}
+			if newest == "" {
+				return out, nil
+			}
+			v, err := rdb.Get(ctx, newest).Result()
+			if errors.Is(err, redis.Nil) {
+				// Expired between SCAN and GET; the count still stands.
+				return out, nil
+			}
+			if err != nil {
+				return out, err
+			}
+			out.Latest = gin.H{"id": strings.TrimPrefix(newest, "item:"), "value": v}
+			return out, nil
+		},
+	})
+}
+
+// handleSummary — per-backend item count and latest item, fetched
+// concurrently under each backend's own timeout. A failing backend reports
+// its error in place; the response is still 200.
+func (a *App) handleSummary(c *gin.Context) {
+	timeouts := map[string]time.Duration{
+		"mongo": time.Duration(a.cfg.MongoTimeout),
+		"redis": time.Duration(a.cfg.RedisTimeout),
+	}
+	summaries := a.itemSummaries()
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		out = make(map[string]backendSummary, len(summaries))
+	)
+	for name, fn := range summaries {
+		wg.Add(1)
+		go func(name string, fn func(context.Context) (backendSummary, error)) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(c.Request.Context(), timeouts[name])
+			defer cancel()
+			res, err := fn(ctx)
+			if err != nil {
+				res.Error = err.Error()
+			}
+			mu.Lock()
+			out[name] = res
+			mu.Unlock()
+		}(name, fn)
+	}
+	wg.Wait()
+	c.JSON(200, gin.H{"backends": out})
+}
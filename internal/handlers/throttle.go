@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+
+	"multi-kind-app/internal/apierr"
+)
+
+// ──────────── Token-Bucket Throttle ────────────
+
+const throttleIdleTTL = 5 * time.Minute
+
+type keyedLimiter struct {
+	lim      *rate.Limiter
+	lastSeen atomic.Int64 // unix nanos
+}
+
+// throttle hands each client IP its own token bucket, and the configured
+// API key one of its own. An X-API-Key that doesn't match apiKey is
+// ignored, so a client can't mint fresh buckets by varying the header.
+// Idle buckets are evicted so memory stays bounded, until stop is closed.
+type throttle struct {
+	rps     rate.Limit
+	burst   int
+	apiKey  string
+	buckets sync.Map // string → *keyedLimiter
+}
+
+func newThrottle(rps float64, burst int, apiKey string, stop <-chan struct{}) *throttle {
+	t := &throttle{rps: rate.Limit(rps), burst: burst, apiKey: apiKey}
+	go t.evictLoop(stop)
+	return t
+}
+
+func (t *throttle) limiter(key string) *keyedLimiter {
+	if v, ok := t.buckets.Load(key); ok {
+		return v.(*keyedLimiter)
+	}
+	v, _ := t.buckets.LoadOrStore(key, &keyedLimiter{lim: rate.NewLimiter(t.rps, t.burst)})
+	return v.(*keyedLimiter)
+}
+
+func (t *throttle) evictLoop(stop <-chan struct{}) {
+	tick := time.NewTicker(time.Minute)
+	defer tick.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-tick.C:
+		}
+		cutoff := time.Now().Add(-throttleIdleTTL).UnixNano()
+		t.buckets.Range(func(k, v any) bool {
+			if v.(*keyedLimiter).lastSeen.Load() < cutoff {
+				t.buckets.Delete(k)
+			}
+			return true
+		})
+	}
+}
+
+func (t *throttle) middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := "ip:" + c.ClientIP()
+		if t.apiKey != "" && subtle.ConstantTimeCompare([]byte(c.GetHeader("X-API-Key")), []byte(t.apiKey)) == 1 {
+			key = "api-key"
+		}
+		kl := t.limiter(key)
+		kl.lastSeen.Store(time.Now().UnixNano())
+
+		allowed := kl.lim.Allow()
+		remaining := int(kl.lim.Tokens())
+		if remaining < 0 {
+			remaining = 0
+		}
+		c.Header("X-RateLimit-Limit", strconv.Itoa(t.burst))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		if !allowed {
+			c.Header("Retry-After", "1")
+			apierr.Abort(c, apierr.New(429, apierr.CodeRateLimited, "rate limit exceeded"))
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,38 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"multi-kind-app/internal/config"
+	"multi-kind-app/internal/handlers/handlertest"
+)
+
+func TestThrottleIgnoresUnknownAPIKeys(t *testing.T) {
+	h := handlertest.New(t, func(c *config.Config) {
+		c.ThrottleRPS, c.ThrottleBurst = 0.001, 2
+		c.AdminAPIKey = "secret"
+	})
+	get := func(key string) int {
+		req := httptest.NewRequest(http.MethodGet, "/throttled/redis/a", nil)
+		req.Header.Set("X-API-Key", key)
+		w := httptest.NewRecorder()
+		h.Router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// A fresh key per request still draws on this client's one bucket.
+	for i, key := range []string{"a", "b", "c"} {
+		want := http.StatusOK
+		if i == 2 {
+			want = http.StatusTooManyRequests
+		}
+		if got := get(key); got != want {
+			t.Fatalf("request %d with key %q: got %d, want %d", i, key, got, want)
+		}
+	}
+	if got := get("secret"); got != http.StatusOK {
+		t.Fatalf("configured key: got %d, want its own bucket", got)
+	}
+}
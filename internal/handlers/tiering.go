@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"multi-kind-app/internal/apierr"
+)
+
+// ──────────── Hot-Tier Promotion ────────────
+
+const (
+	hotPrefix = "hot:item:"
+	hotTTL    = 5 * time.Minute
+)
+
+// handlePromote — copies an item from Mongo into Redis as a hot-tier entry
+// and returns the representation that was cached.
+func (a *App) handlePromote(c *gin.Context) {
+	col, err := a.getItemsCol()
+	if err != nil {
+		unavailable(c, "mongo", err)
+		return
+	}
+	rdb, err := a.getRedis()
+	if err != nil {
+		unavailable(c, "redis", err)
+		return
+	}
+	id := c.Param("id")
+	ctx := c.Request.Context()
+
+	if err := a.injectedFault("mongo"); err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+	var item Item
+	err = col.FindOne(ctx, liveFilter(bson.M{"_id": id})).Decode(&item)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.Error(apierr.NotFound("not found"))
+		return
+	}
+	if err != nil {
+		c.Error(apierr.Wrap(err, "mongo"))
+		return
+	}
+
+	blob, _ := json.Marshal(item)
+	if err := rdb.Set(ctx, hotPrefix+id, blob, hotTTL).Err(); err != nil {
+		c.Error(apierr.Wrap(err, "redis SET"))
+		return
+	}
+	c.JSON(200, gin.H{"tier": "hot", "item": item, "ttl_seconds": int(hotTTL.Seconds())})
+}
+
+// handleDemote — drops the hot-tier copy; the Mongo document is untouched.
+func (a *App) handleDemote(c *gin.Context) {
+	rdb, err := a.getRedis()
+	if err != nil {
+		unavailable(c, "redis", err)
+		return
+	}
+	n, err := rdb.Del(c.Request.Context(), hotPrefix+c.Param("id")).Result()
+	if err != nil {
+		c.Error(apierr.Wrap(err, "redis DEL"))
+		return
+	}
+	c.JSON(200, gin.H{"tier": "cold", "demoted": n > 0})
+}
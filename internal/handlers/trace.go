@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/event"
+
+	"multi-kind-app/internal/apierr"
+)
+
+// ──────────── Call Tracing ────────────
+
+type traceEntry struct {
+	Backend    string  `json:"backend"`
+	Operation  string  `json:"operation"`
+	DurationMs float64 `json:"duration_ms"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// callRecorder collects, in order, every backend call made under a context.
+type callRecorder struct {
+	mu    sync.Mutex
+	calls []traceEntry
+}
+
+type recorderKey struct{}
+
+func withRecorder(ctx context.Context) (context.Context, *callRecorder) {
+	rec := &callRecorder{calls: []traceEntry{}}
+	return context.WithValue(ctx, recorderKey{}, rec), rec
+}
+
+// record appends a call to the context's recorder; without one it is a no-op.
+func record(ctx context.Context, backend, op string, d time.Duration, err error) {
+	rec, ok := ctx.Value(recorderKey{}).(*callRecorder)
+	if !ok {
+		return
+	}
+	e := traceEntry{Backend: backend, Operation: op, DurationMs: float64(d.Microseconds()) / 1000}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	rec.mu.Lock()
+	rec.calls = append(rec.calls, e)
+	rec.mu.Unlock()
+}
+
+// traceHook records Redis commands.
+type traceHook struct{}
+
+func (traceHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (traceHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		// A miss isn't a failure, but callers still need to see it: the
+		// client stores what this returns as the command's error.
+		recorded := err
+		if errors.Is(err, redis.Nil) {
+			recorded = nil
+		}
+		record(ctx, "redis", strings.ToUpper(cmd.Name()), time.Since(start), recorded)
+		return err
+	}
+}
+
+func (traceHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		record(ctx, "redis", "PIPELINE", time.Since(start), err)
+		return err
+	}
+}
+
+// mongoTraceMonitor records Mongo commands; the driver hands the operation's
+// context to the monitor callbacks.
+var mongoTraceMonitor = &event.CommandMonitor{
+	Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+		record(ctx, "mongo", e.CommandName, e.Duration, nil)
+	},
+	Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+		record(ctx, "mongo", e.CommandName, e.Duration, errors.New(e.Failure))
+	},
+}
+
+// traceTransport records outbound HTTP requests.
+type traceTransport struct {
+	base http.RoundTripper
+}
+
+func (t traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	record(req.Context(), "http", req.Method+" "+req.URL.Host+req.URL.Path, time.Since(start), err)
+	return resp, err
+}
+
+// handleTrace — runs GET /<path> through the router and returns the ordered
+// backend calls it made instead of its payload, i.e. exactly what a capture
+// tool should expect to see for that endpoint.
+func handleTrace(engine *gin.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Param("path")
+		if routesTo(path, "/trace", "/batch") {
+			c.Error(apierr.BadRequest("cannot trace " + path))
+			return
+		}
+		if q := c.Request.URL.RawQuery; q != "" {
+			path += "?" + q
+		}
+
+		ctx, rec := withRecorder(c.Request.Context())
+		start := time.Now()
+		res := dispatch(ctx, c.GetString("request_id"), engine, subRequest{Method: http.MethodGet, Path: path})
+
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		c.JSON(200, gin.H{
+			"path":        path,
+			"status":      res.Status,
+			"duration_ms": float64(time.Since(start).Microseconds()) / 1000,
+			"calls":       rec.calls,
+		})
+	}
+}
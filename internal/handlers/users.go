@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"multi-kind-app/internal/apierr"
+	"multi-kind-app/internal/storage"
+)
+
+// ──────────── Users ────────────
+
+type credentials struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// handleRegister — 201 with the new user; the password is only ever stored
+// as a bcrypt hash.
+func (a *App) handleRegister(c *gin.Context) {
+	var req credentials
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apierr.BadRequest("body must be {\"username\": ..., \"password\": ...}"))
+		return
+	}
+	u, err := a.users.Register(c.Request.Context(), req.Username, req.Password)
+	if errors.Is(err, storage.ErrConflict) {
+		c.Error(apierr.Conflict("username is taken"))
+		return
+	}
+	if err != nil {
+		c.Error(apierr.Wrap(err, "register"))
+		return
+	}
+	c.JSON(201, gin.H{"user": u})
+}
+
+// handleLogin — 200 with the user, or 401 without saying whether the
+// username or the password was wrong.
+func (a *App) handleLogin(c *gin.Context) {
+	var req credentials
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apierr.BadRequest("body must be {\"username\": ..., \"password\": ...}"))
+		return
+	}
+	u, err := a.users.Login(c.Request.Context(), req.Username, req.Password)
+	if err != nil {
+		c.Error(apierr.Wrap(err, "login"))
+		return
+	}
+	c.JSON(200, gin.H{"user": u})
+}
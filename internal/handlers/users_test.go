@@ -0,0 +1,27 @@
+package handlers_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"multi-kind-app/internal/apierr"
+	"multi-kind-app/internal/handlers/handlertest"
+)
+
+func TestRegisterAndLogin(t *testing.T) {
+	h := handlertest.New(t)
+	creds := map[string]string{"username": "alice", "password": "correct horse"}
+
+	w := h.Do(http.MethodPost, "/users/register", creds)
+	if w.Code != http.StatusCreated || strings.Contains(w.Body.String(), "password") {
+		t.Fatalf("register: got %d %s", w.Code, w.Body)
+	}
+	wantProblem(t, h.Do(http.MethodPost, "/users/register", creds), http.StatusConflict, apierr.CodeConflict)
+
+	if w := h.Do(http.MethodPost, "/users/login", creds); w.Code != http.StatusOK {
+		t.Fatalf("login: got %d %s", w.Code, w.Body)
+	}
+	creds["password"] = "wrong horse"
+	wantProblem(t, h.Do(http.MethodPost, "/users/login", creds), http.StatusUnauthorized, apierr.CodeUnauthorized)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"multi-kind-app/internal/apierr"
+)
+
+// ──────────── API Versions ────────────
+
+// apiVersionHeader names the version a request wants on an unversioned
+// path, and the version that answered on every API response.
+const apiVersionHeader = "API-Version"
+
+// apiVersions are served under /v{n}. Unversioned paths default to the
+// first, so clients written before versioning keep their contract;
+// breaking response-shape changes land in a new version only.
+var apiVersions = []int{1, 2}
+
+// apiVersion is the version serving c, or 0 outside the API routes.
+func apiVersion(c *gin.Context) int {
+	return c.GetInt("api_version")
+}
+
+// pinVersion serves a /v{n} group. The path wins over any API-Version
+// header.
+func pinVersion(v int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("api_version", v)
+		c.Header(apiVersionHeader, strconv.Itoa(v))
+		c.Next()
+	}
+}
+
+// negotiateVersion serves the unversioned paths: API-Version ("2" or
+// "v2") picks the version, and its absence means the oldest.
+func negotiateVersion() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		v := apiVersions[0]
+		if h := c.GetHeader(apiVersionHeader); h != "" {
+			n, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(h), "v"))
+			if err != nil || !knownVersion(n) {
+				apierr.Abort(c, apierr.BadRequest("unsupported "+apiVersionHeader+": "+h))
+				return
+			}
+			v = n
+		}
+		c.Set("api_version", v)
+		c.Header(apiVersionHeader, strconv.Itoa(v))
+		c.Next()
+	}
+}
+
+func knownVersion(v int) bool {
+	for _, known := range apiVersions {
+		if v == known {
+			return true
+		}
+	}
+	return false
+}
+
+// unversionedRoute strips a /v{n} prefix from a route pattern, so
+// per-route settings such as SNAPSHOT_ROUTES apply to every version.
+func unversionedRoute(route string) string {
+	for _, v := range apiVersions {
+		if rest, ok := strings.CutPrefix(route, "/v"+strconv.Itoa(v)+"/"); ok {
+			return "/" + rest
+		}
+	}
+	return route
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"multi-kind-app/internal/apierr"
+)
+
+// ──────────── Webhook Dispatch ────────────
+
+const webhookAttempts = 3
+
+// handleWebhook — records an audit entry in Mongo, then POSTs the payload to
+// WEBHOOK_URL and reports the downstream status.
+func (a *App) handleWebhook(c *gin.Context) {
+	mdb, err := a.getMongo()
+	if err != nil {
+		unavailable(c, "mongo", err)
+		return
+	}
+	url := a.cfg.WebhookURL
+	if url == "" {
+		c.Error(apierr.BadRequest("WEBHOOK_URL is not configured"))
+		return
+	}
+	var payload map[string]any
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.Error(apierr.BadRequest(err.Error()))
+		return
+	}
+	ctx := c.Request.Context()
+
+	audit := bson.M{"event": "webhook.trigger", "target": url, "payload": payload, "at": time.Now().UTC()}
+	if err := a.injectedFault("mongo"); err != nil {
+		c.Error(apierr.Wrap(err, "mongo audit"))
+		return
+	}
+	res, err := mdb.Collection("audit").InsertOne(ctx, audit)
+	if err != nil {
+		c.Error(apierr.Wrap(err, "mongo audit"))
+		return
+	}
+
+	status, attempts, err := a.postJSON(ctx, url, payload)
+	if err != nil {
+		c.Error(apierr.Upstream(err, "webhook POST").With("attempts", attempts).With("audit_id", res.InsertedID))
+		return
+	}
+	c.JSON(200, gin.H{"downstream_status": status, "attempts": attempts, "audit_id": res.InsertedID})
+}
+
+// postJSON POSTs payload to url, retrying network errors and 5xx responses.
+func (a *App) postJSON(ctx context.Context, url string, payload any) (status, attempts int, err error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, 0, err
+	}
+	for attempts = 1; ; attempts++ {
+		status, err = a.postOnce(ctx, url, body)
+		if err == nil && status < 500 {
+			return status, attempts, nil
+		}
+		if err == nil {
+			err = fmt.Errorf("downstream returned %d", status)
+		}
+		if attempts == webhookAttempts {
+			return status, attempts, err
+		}
+		select {
+		case <-ctx.Done():
+			return status, attempts, ctx.Err()
+		case <-time.After(time.Duration(attempts) * 200 * time.Millisecond):
+		}
+	}
+}
+
+func (a *App) postOnce(ctx context.Context, url string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+)
+
+// ──────────── Item Cache ────────────
+
+// CacheService caches item lookups per backend so repeat reads skip the
+// round trips. Writes must Invalidate what they change.
+type CacheService struct {
+	lru *lruCache
+}
+
+// NewCacheService holds up to capacity entries; 0 disables caching.
+func NewCacheService(capacity int) *CacheService {
+	return &CacheService{lru: newLRUCache(capacity)}
+}
+
+func (s *CacheService) Get(backend, id string) (any, bool) {
+	return s.lru.Get(cacheKey(backend, id))
+}
+
+func (s *CacheService) Put(backend, id string, v any) {
+	s.lru.Add(cacheKey(backend, id), v)
+}
+
+func (s *CacheService) Invalidate(backend, id string) {
+	s.lru.Remove(cacheKey(backend, id))
+}
+
+// InvalidateBackend drops every entry of backend, for writes that don't
+// know which ids they changed.
+func (s *CacheService) InvalidateBackend(backend string) {
+	s.lru.RemovePrefix(cacheKey(backend, ""))
+}
+
+func cacheKey(backend, id string) string {
+	return backend + ":" + id
+}
+
+// lruCache is a small, bounded, mutex-guarded LRU. A capacity of 0 disables
+// it: Get always misses and Add is a no-op.
+type lruCache struct {
+	mu    sync.Mutex
+	cap   int
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+type lruEntry struct {
+	key string
+	val any
+}
+
+func newLRUCache(capacity int) *lruCache {
+	return &lruCache{cap: capacity, ll: list.New(), items: map[string]*list.Element{}}
+}
+
+func (l *lruCache) Get(key string) (any, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	el, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+	l.ll.MoveToFront(el)
+	return el.Value.(*lruEntry).val, true
+}
+
+func (l *lruCache) Add(key string, val any) {
+	if l.cap <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if el, ok := l.items[key]; ok {
+		el.Value.(*lruEntry).val = val
+		l.ll.MoveToFront(el)
+		return
+	}
+	l.items[key] = l.ll.PushFront(&lruEntry{key: key, val: val})
+	if l.ll.Len() > l.cap {
+		oldest := l.ll.Back()
+		l.ll.Remove(oldest)
+		delete(l.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (l *lruCache) Remove(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if el, ok := l.items[key]; ok {
+		l.ll.Remove(el)
+		delete(l.items, key)
+	}
+}
+
+func (l *lruCache) RemovePrefix(prefix string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for key, el := range l.items {
+		if strings.HasPrefix(key, prefix) {
+			l.ll.Remove(el)
+			delete(l.items, key)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ──────────── IDs ────────────
+
+// IDFunc mints the id of a record created without one.
+type IDFunc func() string
+
+// NewObjectID is the default IDFunc: an ObjectID in hex. ObjectIDs start
+// with their creation time, so they sort in creation order.
+func NewObjectID() string {
+	return primitive.NewObjectID().Hex()
+}
+
+// NewUUID is the IDFunc for id_mode uuid: a random v4 UUID, which says
+// nothing about when, or after what, a record was created.
+func NewUUID() string {
+	return uuid.NewString()
+}
+
+// IDFuncFor returns the IDFunc for an id_mode setting.
+func IDFuncFor(mode string) IDFunc {
+	if mode == "uuid" {
+		return NewUUID
+	}
+	return NewObjectID
+}
@@ -0,0 +1,318 @@
+// Package service holds the app's business logic, independent of how it is
+// exposed: the Gin handlers call it today, and another transport would
+// call the same methods.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"multi-kind-app/internal/storage"
+)
+
+// ──────────── Items ────────────
+
+// ErrUnknownStore is returned for a store name with no repository.
+var ErrUnknownStore = errors.New("unknown store")
+
+// ItemService validates, mints ids and pages over the ItemRepository of
+// each named store.
+type ItemService struct {
+	repos map[string]storage.ItemRepository
+	newID IDFunc
+}
+
+func NewItemService(repos map[string]storage.ItemRepository, newID IDFunc) *ItemService {
+	return &ItemService{repos: repos, newID: newID}
+}
+
+// Stores returns the store names, sorted.
+func (s *ItemService) Stores() []string {
+	names := make([]string, 0, len(s.repos))
+	for name := range s.repos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Has reports whether store has a repository.
+func (s *ItemService) Has(store string) bool {
+	_, ok := s.repos[store]
+	return ok
+}
+
+func (s *ItemService) repo(store string) (storage.ItemRepository, error) {
+	r, ok := s.repos[store]
+	if !ok {
+		return nil, ErrUnknownStore
+	}
+	return r, nil
+}
+
+// NewID mints an id for an item created without one.
+func (s *ItemService) NewID() string {
+	return s.newID()
+}
+
+// Create stores item after sanitising its name, minting an id when it has
+// none. Bad input is an *InvalidError.
+func (s *ItemService) Create(ctx context.Context, store string, item storage.Item) (storage.Item, error) {
+	r, err := s.repo(store)
+	if err != nil {
+		return storage.Item{}, err
+	}
+	if item.Name, err = SanitizeName(item.Name); err != nil {
+		return storage.Item{}, err
+	}
+	if item.ID == "" {
+		item.ID = s.newID()
+	}
+	return r.Create(ctx, item)
+}
+
+// UpsertByName sets the value of the item named name in store, creating
+// it when there is none. created reports which happened. Stores whose
+// repository isn't a storage.Upserter fail with storage.ErrInvalidQuery.
+func (s *ItemService) UpsertByName(ctx context.Context, store, name, value string) (item storage.Item, created bool, err error) {
+	r, err := s.repo(store)
+	if err != nil {
+		return storage.Item{}, false, err
+	}
+	up, ok := r.(storage.Upserter)
+	if !ok {
+		return storage.Item{}, false, fmt.Errorf("%w: the %s store can't look items up by name", storage.ErrInvalidQuery, store)
+	}
+	if name, err = SanitizeName(name); err != nil {
+		return storage.Item{}, false, err
+	}
+	if name == "" {
+		return storage.Item{}, false, invalid("name must not be blank")
+	}
+	return up.UpsertByName(ctx, storage.Item{ID: s.newID(), Name: name, Value: value})
+}
+
+// Replace writes item whole under its id after sanitising its name,
+// creating it when the id is new and minting one when it has none. The
+// stored CreatedAt and DeletedAt are kept, whatever item carries. created
+// reports whether the id was new. Stores whose repository isn't a
+// storage.Replacer fail with storage.ErrInvalidQuery.
+func (s *ItemService) Replace(ctx context.Context, store string, item storage.Item) (stored storage.Item, created bool, err error) {
+	r, err := s.repo(store)
+	if err != nil {
+		return storage.Item{}, false, err
+	}
+	rep, ok := r.(storage.Replacer)
+	if !ok {
+		return storage.Item{}, false, fmt.Errorf("%w: the %s store can't replace items", storage.ErrInvalidQuery, store)
+	}
+	if item.Name, err = SanitizeName(item.Name); err != nil {
+		return storage.Item{}, false, err
+	}
+	if item.ID == "" {
+		item.ID = s.newID()
+	}
+	item.CreatedAt, item.DeletedAt = nil, nil
+	created, err = rep.Replace(ctx, item)
+	return item, created, err
+}
+
+// Get returns the live item id in store, or storage.ErrNotFound. Stores
+// whose repository isn't a storage.Getter fail with
+// storage.ErrInvalidQuery.
+func (s *ItemService) Get(ctx context.Context, store, id string) (storage.Item, error) {
+	g, err := s.getter(store)
+	if err != nil {
+		return storage.Item{}, err
+	}
+	return g.Get(ctx, id)
+}
+
+// GetMany returns the live items among ids in the order they were asked
+// for, and the ids with no live item.
+func (s *ItemService) GetMany(ctx context.Context, store string, ids []string) (items []storage.Item, missing []string, err error) {
+	g, err := s.getter(store)
+	if err != nil {
+		return nil, nil, err
+	}
+	found, err := g.GetMany(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[string]storage.Item, len(found))
+	for _, item := range found {
+		byID[item.ID] = item
+	}
+	items, missing = make([]storage.Item, 0, len(ids)), []string{}
+	for _, id := range ids {
+		if item, ok := byID[id]; ok {
+			items = append(items, item)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	return items, missing, nil
+}
+
+func (s *ItemService) getter(store string) (storage.Getter, error) {
+	r, err := s.repo(store)
+	if err != nil {
+		return nil, err
+	}
+	g, ok := r.(storage.Getter)
+	if !ok {
+		return nil, fmt.Errorf("%w: the %s store can't look items up by id", storage.ErrInvalidQuery, store)
+	}
+	return g, nil
+}
+
+// Result is the outcome of one item of CreateMany: the stored item, or why
+// it wasn't stored.
+type Result struct {
+	Item storage.Item
+	Err  error
+}
+
+// CreateMany validates and stores items, index-aligned with the results.
+// Items that fail validation are never sent to the store; the rest go in
+// one CreateMany when the repository is a storage.BulkCreator, one Create
+// at a time otherwise. The error is only for an unknown store.
+func (s *ItemService) CreateMany(ctx context.Context, store string, items []storage.Item) ([]Result, error) {
+	r, err := s.repo(store)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]Result, len(items))
+	valid := make([]storage.Item, 0, len(items))
+	at := make([]int, 0, len(items)) // index in items of each valid item
+	for i, item := range items {
+		if item.Name, err = SanitizeName(item.Name); err != nil {
+			results[i] = Result{Item: item, Err: err}
+			continue
+		}
+		if item.ID == "" {
+			item.ID = s.newID()
+		}
+		valid = append(valid, item)
+		at = append(at, i)
+	}
+
+	if bulk, ok := r.(storage.BulkCreator); ok && len(valid) > 0 {
+		created, errs := bulk.CreateMany(ctx, valid)
+		for j, i := range at {
+			results[i] = Result{Item: created[j], Err: errs[j]}
+			if errs[j] != nil {
+				results[i].Item = valid[j]
+			}
+		}
+		return results, nil
+	}
+	for j, i := range at {
+		created, err := r.Create(ctx, valid[j])
+		if err != nil {
+			created = valid[j]
+		}
+		results[i] = Result{Item: created, Err: err}
+	}
+	return results, nil
+}
+
+// Latest returns the most recently created item.
+func (s *ItemService) Latest(ctx context.Context, store string) (storage.Item, error) {
+	r, err := s.repo(store)
+	if err != nil {
+		return storage.Item{}, err
+	}
+	return r.GetLatest(ctx)
+}
+
+// Page is one slice of a listing. Total counts every item the listing
+// pages over; NextOffset is set when more items remain.
+type Page struct {
+	Items      []storage.Item `json:"items"`
+	Total      int64          `json:"total"`
+	Limit      int64          `json:"limit"`
+	Offset     int64          `json:"offset"`
+	NextOffset *int64         `json:"next_offset,omitempty"`
+}
+
+// List returns the page q selects, with the total alongside.
+func (s *ItemService) List(ctx context.Context, store string, q storage.ListQuery) (Page, error) {
+	r, err := s.repo(store)
+	if err != nil {
+		return Page{}, err
+	}
+	items, err := r.List(ctx, q)
+	if err != nil {
+		return Page{}, err
+	}
+	total, err := r.Count(ctx, q)
+	if err != nil {
+		return Page{}, err
+	}
+	page := Page{Items: items, Total: total, Limit: q.Limit, Offset: q.Offset}
+	if next := q.Offset + int64(len(items)); len(items) > 0 && next < total {
+		page.NextOffset = &next
+	}
+	return page, nil
+}
+
+func (s *ItemService) Delete(ctx context.Context, store, id string) error {
+	r, err := s.repo(store)
+	if err != nil {
+		return err
+	}
+	return r.Delete(ctx, id)
+}
+
+// Restore undeletes a soft-deleted item in store.
+func (s *ItemService) Restore(ctx context.Context, store, id string) (storage.Item, error) {
+	r, err := s.repo(store)
+	if err != nil {
+		return storage.Item{}, err
+	}
+	return r.Restore(ctx, id)
+}
+
+// DeleteMany soft-deletes every live item matching q's filters and
+// returns how many went. At least one filter is required, so it can't
+// empty a store by accident. Repositories that aren't a
+// storage.BulkDeleter are paged through and deleted one item at a time.
+func (s *ItemService) DeleteMany(ctx context.Context, store string, q storage.ListQuery) (int64, error) {
+	r, err := s.repo(store)
+	if err != nil {
+		return 0, err
+	}
+	if q.Name == "" && q.CreatedAfter.IsZero() && q.CreatedBefore.IsZero() {
+		return 0, invalid("refusing to delete without a filter")
+	}
+	if bulk, ok := r.(storage.BulkDeleter); ok {
+		return bulk.DeleteMany(ctx, q)
+	}
+	var deleted int64
+	for {
+		items, err := r.List(ctx, storage.ListQuery{
+			Limit: 100, Name: q.Name, CreatedAfter: q.CreatedAfter, CreatedBefore: q.CreatedBefore,
+		})
+		if err != nil || len(items) == 0 {
+			return deleted, err
+		}
+		// A page that lists items but deletes none would come back
+		// unchanged forever, so stop instead.
+		var pass int64
+		for _, item := range items {
+			switch err := r.Delete(ctx, item.ID); {
+			case err == nil:
+				pass++
+			case !errors.Is(err, storage.ErrNotFound):
+				return deleted, err
+			}
+		}
+		if pass == 0 {
+			return deleted, fmt.Errorf("delete made no progress: %d listed items were not found", len(items))
+		}
+		deleted += pass
+	}
+}
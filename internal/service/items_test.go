@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"multi-kind-app/internal/storage"
+	"multi-kind-app/internal/storage/memstore"
+)
+
+func TestItemServiceCreateAndList(t *testing.T) {
+	ctx := context.Background()
+	s := NewItemService(map[string]storage.ItemRepository{"mem": memstore.NewItemRepo()}, NewUUID)
+
+	if _, err := s.Create(ctx, "nope", storage.Item{Name: "a"}); !errors.Is(err, ErrUnknownStore) {
+		t.Fatalf("unknown store: got %v", err)
+	}
+	var invalid *InvalidError
+	if _, err := s.Create(ctx, "mem", storage.Item{Name: "a\x00b"}); !errors.As(err, &invalid) {
+		t.Fatalf("control character: got %v", err)
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		item, err := s.Create(ctx, "mem", storage.Item{Name: " " + name + " "})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := uuid.Parse(item.ID); err != nil || item.Name != name {
+			t.Fatalf("create: got %+v", item)
+		}
+	}
+
+	page, err := s.List(ctx, "mem", storage.ListQuery{Limit: 2})
+	if err != nil || len(page.Items) != 2 || page.Total != 3 || page.NextOffset == nil || *page.NextOffset != 2 {
+		t.Fatalf("first page: got %+v, %v", page, err)
+	}
+	page, err = s.List(ctx, "mem", storage.ListQuery{Limit: 2, Offset: 2})
+	if err != nil || len(page.Items) != 1 || page.NextOffset != nil {
+		t.Fatalf("last page: got %+v, %v", page, err)
+	}
+}
+
+func TestItemServiceUpsertByName(t *testing.T) {
+	ctx := context.Background()
+	s := NewItemService(map[string]storage.ItemRepository{
+		"mem": memstore.NewItemRepo(),
+		// Hides the Upserter, like a store with no name lookup.
+		"plain": struct{ storage.ItemRepository }{memstore.NewItemRepo()},
+	}, NewObjectID)
+
+	first, created, err := s.UpsertByName(ctx, "mem", " a ", "1")
+	if err != nil || !created || first.Name != "a" {
+		t.Fatalf("first upsert: got %+v, %v, %v", first, created, err)
+	}
+	second, created, err := s.UpsertByName(ctx, "mem", "a", "2")
+	if err != nil || created || second.ID != first.ID || second.Value != "2" {
+		t.Fatalf("second upsert: got %+v, %v, %v", second, created, err)
+	}
+	if _, _, err := s.UpsertByName(ctx, "plain", "a", "1"); !errors.Is(err, storage.ErrInvalidQuery) {
+		t.Fatalf("store without upsert: got %v", err)
+	}
+}
+
+func TestItemServiceReplaceAndGetMany(t *testing.T) {
+	ctx := context.Background()
+	s := NewItemService(map[string]storage.ItemRepository{
+		"mem":   memstore.NewItemRepo(),
+		"plain": struct{ storage.ItemRepository }{memstore.NewItemRepo()},
+	}, NewObjectID)
+
+	item, created, err := s.Replace(ctx, "mem", storage.Item{ID: "b", Name: " b "})
+	if err != nil || !created || item.Name != "b" {
+		t.Fatalf("replace: got %+v, %v, %v", item, created, err)
+	}
+	if _, created, err := s.Replace(ctx, "mem", storage.Item{ID: "b", Name: "b2"}); err != nil || created {
+		t.Fatalf("second replace: got %v, %v", created, err)
+	}
+	if _, err := s.Create(ctx, "mem", storage.Item{ID: "a", Name: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	items, missing, err := s.GetMany(ctx, "mem", []string{"b", "x", "a"})
+	if err != nil || len(items) != 2 || items[0].Name != "b2" || items[1].ID != "a" || len(missing) != 1 || missing[0] != "x" {
+		t.Fatalf("get many: got %+v, %v, %v", items, missing, err)
+	}
+	if _, err := s.Get(ctx, "plain", "a"); !errors.Is(err, storage.ErrInvalidQuery) {
+		t.Fatalf("store without get: got %v", err)
+	}
+	if _, _, err := s.Replace(ctx, "plain", storage.Item{Name: "a"}); !errors.Is(err, storage.ErrInvalidQuery) {
+		t.Fatalf("store without replace: got %v", err)
+	}
+}
+
+// notFoundRepo lists items it then can't delete, and hides the
+// BulkDeleter so DeleteMany takes the page-by-page path.
+type notFoundRepo struct{ storage.ItemRepository }
+
+func (notFoundRepo) Delete(context.Context, string) error { return storage.ErrNotFound }
+
+func TestItemServiceDeleteManyStopsWithoutProgress(t *testing.T) {
+	ctx := context.Background()
+	repo := memstore.NewItemRepo()
+	s := NewItemService(map[string]storage.ItemRepository{"stuck": notFoundRepo{repo}}, NewObjectID)
+	if _, err := s.Create(ctx, "stuck", storage.Item{Name: "a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.DeleteMany(ctx, "stuck", storage.ListQuery{Name: "a"})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("DeleteMany: got nil error, want no-progress error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("DeleteMany did not return")
+	}
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"multi-kind-app/internal/storage"
+)
+
+// ──────────── Users ────────────
+
+// ErrInvalidCredentials is returned by Login for an unknown username or a
+// wrong password; callers can't tell which.
+var ErrInvalidCredentials = errors.New("invalid username or password")
+
+const (
+	minUsernameLen = 3
+	maxUsernameLen = 32
+	minPasswordLen = 8
+	// maxPasswordLen is bcrypt's limit; longer passwords would be
+	// silently truncated.
+	maxPasswordLen = 72
+)
+
+// UserService registers users and checks their passwords. Passwords are
+// stored as bcrypt hashes only.
+type UserService struct {
+	repo  storage.UserRepository
+	cost  int
+	newID IDFunc
+	// dummyHash is compared against when the username is unknown, so a
+	// failed login takes as long whether or not the user exists. It is
+	// built on first use.
+	dummyOnce sync.Once
+	dummyHash []byte
+}
+
+// NewUserService hashes with cost; pass bcrypt.DefaultCost outside tests.
+// User ids come from newID.
+func NewUserService(repo storage.UserRepository, cost int, newID IDFunc) *UserService {
+	return &UserService{repo: repo, cost: cost, newID: newID}
+}
+
+// normalizeUsername lowercases and checks a username: 3 to 32 letters,
+// digits, '.', '_' or '-'.
+func normalizeUsername(s string) (string, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if n := len(s); n < minUsernameLen || n > maxUsernameLen {
+		return "", invalid("username must be %d to %d characters", minUsernameLen, maxUsernameLen)
+	}
+	for _, r := range s {
+		if !('a' <= r && r <= 'z' || '0' <= r && r <= '9' || r == '.' || r == '_' || r == '-') {
+			return "", invalid("username may only contain letters, digits, '.', '_' and '-'")
+		}
+	}
+	return s, nil
+}
+
+// Register creates a user. A taken username fails with storage.ErrConflict,
+// a bad username or password with an *InvalidError.
+func (s *UserService) Register(ctx context.Context, username, password string) (storage.User, error) {
+	username, err := normalizeUsername(username)
+	if err != nil {
+		return storage.User{}, err
+	}
+	if n := len(password); n < minPasswordLen || n > maxPasswordLen {
+		return storage.User{}, invalid("password must be %d to %d bytes", minPasswordLen, maxPasswordLen)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), s.cost)
+	if err != nil {
+		return storage.User{}, err
+	}
+	return s.repo.Create(ctx, storage.User{ID: s.newID(), Username: username, PasswordHash: hash})
+}
+
+// Login returns the user when password matches, ErrInvalidCredentials
+// otherwise.
+func (s *UserService) Login(ctx context.Context, username, password string) (storage.User, error) {
+	username = strings.ToLower(strings.TrimSpace(username))
+	u, err := s.repo.GetByUsername(ctx, username)
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		s.dummyOnce.Do(func() {
+			s.dummyHash, _ = bcrypt.GenerateFromPassword([]byte("not a real password"), s.cost)
+		})
+		bcrypt.CompareHashAndPassword(s.dummyHash, []byte(password))
+		return storage.User{}, ErrInvalidCredentials
+	case err != nil:
+		return storage.User{}, err
+	}
+	if bcrypt.CompareHashAndPassword(u.PasswordHash, []byte(password)) != nil {
+		return storage.User{}, ErrInvalidCredentials
+	}
+	return u, nil
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"multi-kind-app/internal/storage"
+	"multi-kind-app/internal/storage/memstore"
+)
+
+func TestUserServiceRegisterAndLogin(t *testing.T) {
+	ctx := context.Background()
+	s := NewUserService(memstore.NewUserRepo(), bcrypt.MinCost, NewObjectID)
+
+	u, err := s.Register(ctx, " Alice ", "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Username != "alice" || string(u.PasswordHash) == "correct horse" {
+		t.Fatalf("register: got %+v", u)
+	}
+	if _, err := s.Register(ctx, "ALICE", "another pass"); !errors.Is(err, storage.ErrConflict) {
+		t.Fatalf("duplicate: got %v", err)
+	}
+	var invalid *InvalidError
+	for _, bad := range [][2]string{{"al", "long enough"}, {"al ice", "long enough"}, {"bob", "short"}} {
+		if _, err := s.Register(ctx, bad[0], bad[1]); !errors.As(err, &invalid) {
+			t.Errorf("register %q/%q: got %v", bad[0], bad[1], err)
+		}
+	}
+
+	if _, err := s.Login(ctx, "alice", "correct horse"); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	for _, bad := range [][2]string{{"alice", "wrong horse"}, {"nobody", "correct horse"}} {
+		if _, err := s.Login(ctx, bad[0], bad[1]); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("login %q: got %v", bad[0], err)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ──────────── Input Validation ────────────
+
+// maxNameLen matches the widest name column the demo has ever used
+// (VARCHAR(255)), so every backend accepts the same set of names.
+const maxNameLen = 255
+
+// InvalidError is a rejected input. Its message is safe to show the
+// client as is.
+type InvalidError struct{ msg string }
+
+func (e *InvalidError) Error() string { return e.msg }
+
+func invalid(format string, args ...any) error {
+	return &InvalidError{fmt.Sprintf(format, args...)}
+}
+
+// SanitizeName trims surrounding whitespace and rejects names containing
+// control characters or longer than maxNameLen runes.
+// Every write handler runs user-supplied names through it before touching a
+// backend.
+func SanitizeName(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if !utf8.ValidString(s) {
+		return "", invalid("name must be valid UTF-8")
+	}
+	if n := utf8.RuneCountInString(s); n > maxNameLen {
+		return "", invalid("name is %d characters, max is %d", n, maxNameLen)
+	}
+	for _, r := range s {
+		if unicode.IsControl(r) {
+			return "", invalid("name contains control character %U", r)
+		}
+	}
+	return s, nil
+}
+
+// CopyName is name with " (copy)" appended, with name cut short where the
+// result would otherwise be longer than maxNameLen.
+func CopyName(name string) string {
+	const suffix = " (copy)"
+	if r := []rune(name); len(r)+len(suffix) > maxNameLen {
+		name = strings.TrimRightFunc(string(r[:maxNameLen-len(suffix)]), unicode.IsSpace)
+	}
+	return name + suffix
+}
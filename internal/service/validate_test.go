@@ -0,0 +1,21 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestCopyNameFitsMaxLen(t *testing.T) {
+	if got := CopyName("a"); got != "a (copy)" {
+		t.Fatalf("CopyName(a) = %q", got)
+	}
+	long := strings.Repeat("é", maxNameLen)
+	got := CopyName(long)
+	if n := utf8.RuneCountInString(got); n != maxNameLen || !strings.HasSuffix(got, " (copy)") {
+		t.Fatalf("CopyName(long) has %d runes: %q", n, got)
+	}
+	if _, err := SanitizeName(got); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,187 @@
+package memstore
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"multi-kind-app/internal/storage"
+)
+
+// ItemRepo is a map-backed storage.ItemRepository with the same ordering
+// and errors as the Mongo and Redis ones, for tests and local runs without
+// a database.
+type ItemRepo struct {
+	mu    sync.RWMutex
+	items map[string]storage.Item
+}
+
+var (
+	_ storage.ItemRepository = (*ItemRepo)(nil)
+	_ storage.Upserter       = (*ItemRepo)(nil)
+	_ storage.Getter         = (*ItemRepo)(nil)
+	_ storage.Replacer       = (*ItemRepo)(nil)
+)
+
+func NewItemRepo() *ItemRepo {
+	return &ItemRepo{items: map[string]storage.Item{}}
+}
+
+func (r *ItemRepo) Create(_ context.Context, item storage.Item) (storage.Item, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.items[item.ID]; ok {
+		return storage.Item{}, storage.ErrConflict
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	item.CreatedAt = &now
+	r.items[item.ID] = item
+	return item, nil
+}
+
+func (r *ItemRepo) UpsertByName(_ context.Context, item storage.Item) (storage.Item, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found *storage.Item
+	for _, it := range r.items {
+		if it.Name != item.Name || it.DeletedAt != nil {
+			continue
+		}
+		if found == nil || it.CreatedAt.Before(*found.CreatedAt) ||
+			it.CreatedAt.Equal(*found.CreatedAt) && it.ID < found.ID {
+			found = &it
+		}
+	}
+	if found != nil {
+		found.Value = item.Value
+		r.items[found.ID] = *found
+		return *found, false, nil
+	}
+	if _, ok := r.items[item.ID]; ok {
+		return storage.Item{}, false, storage.ErrConflict
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	item.CreatedAt = &now
+	r.items[item.ID] = item
+	return item, true, nil
+}
+
+func (r *ItemRepo) Get(_ context.Context, id string) (storage.Item, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	item, ok := r.items[id]
+	if !ok || item.DeletedAt != nil {
+		return storage.Item{}, storage.ErrNotFound
+	}
+	return item, nil
+}
+
+func (r *ItemRepo) GetMany(ctx context.Context, ids []string) ([]storage.Item, error) {
+	items := []storage.Item{}
+	for _, id := range ids {
+		if item, err := r.Get(ctx, id); err == nil {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (r *ItemRepo) Replace(_ context.Context, item storage.Item) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old, ok := r.items[item.ID]
+	item.CreatedAt, item.DeletedAt = old.CreatedAt, old.DeletedAt
+	if !ok {
+		now := time.Now().UTC().Truncate(time.Millisecond)
+		item.CreatedAt = &now
+	}
+	r.items[item.ID] = item
+	return !ok, nil
+}
+
+func (r *ItemRepo) GetLatest(ctx context.Context) (storage.Item, error) {
+	items, _ := r.List(ctx, storage.ListQuery{Limit: 1})
+	if len(items) == 0 {
+		return storage.Item{}, storage.ErrNotFound
+	}
+	return items[0], nil
+}
+
+// List orders by q.Sort, ties broken by id in the same direction. A zero
+// Limit means no limit.
+func (r *ItemRepo) List(_ context.Context, q storage.ListQuery) ([]storage.Item, error) {
+	r.mu.RLock()
+	items := make([]storage.Item, 0, len(r.items))
+	for _, item := range r.items {
+		if q.Match(item) {
+			items = append(items, item)
+		}
+	}
+	r.mu.RUnlock()
+
+	field, desc := q.Sort.Field()
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if desc {
+			a, b = b, a
+		}
+		switch field {
+		case "created_at":
+			if !a.CreatedAt.Equal(*b.CreatedAt) {
+				return a.CreatedAt.Before(*b.CreatedAt)
+			}
+		case "name":
+			if a.Name != b.Name {
+				return a.Name < b.Name
+			}
+		}
+		return a.ID < b.ID
+	})
+	start := min(q.Offset, int64(len(items)))
+	end := int64(len(items))
+	if q.Limit > 0 {
+		end = min(start+q.Limit, end)
+	}
+	return items[start:end], nil
+}
+
+func (r *ItemRepo) Count(_ context.Context, q storage.ListQuery) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var n int64
+	for _, item := range r.items {
+		if q.Match(item) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *ItemRepo) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.items[id]
+	if !ok || item.DeletedAt != nil {
+		return storage.ErrNotFound
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	item.DeletedAt = &now
+	r.items[id] = item
+	return nil
+}
+
+func (r *ItemRepo) Restore(_ context.Context, id string) (storage.Item, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.items[id]
+	switch {
+	case !ok:
+		return storage.Item{}, storage.ErrNotFound
+	case item.DeletedAt == nil:
+		return storage.Item{}, storage.ErrConflict
+	}
+	item.DeletedAt = nil
+	r.items[id] = item
+	return item, nil
+}
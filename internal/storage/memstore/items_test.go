@@ -0,0 +1,112 @@
+package memstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"multi-kind-app/internal/storage"
+)
+
+func TestItemRepoOrderAndPaging(t *testing.T) {
+	ctx := context.Background()
+	r := NewItemRepo()
+	for _, id := range []string{"a", "b", "c", "d"} {
+		if _, err := r.Create(ctx, storage.Item{ID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Newest first; items created in the same millisecond fall back to
+	// id order, which here is the same order.
+	items, _ := r.List(ctx, storage.ListQuery{Limit: 2, Offset: 1})
+	if len(items) != 2 || items[0].ID != "c" || items[1].ID != "b" {
+		t.Fatalf("page: got %+v", items)
+	}
+	if items, _ := r.List(ctx, storage.ListQuery{Offset: 10}); len(items) != 0 {
+		t.Fatalf("offset past end: got %d items", len(items))
+	}
+	latest, err := r.GetLatest(ctx)
+	if err != nil || latest.ID != "d" {
+		t.Fatalf("latest: got %+v, %v", latest, err)
+	}
+}
+
+func TestItemRepoErrors(t *testing.T) {
+	ctx := context.Background()
+	r := NewItemRepo()
+	if _, err := r.GetLatest(ctx); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("latest on empty: got %v", err)
+	}
+	if _, err := r.Create(ctx, storage.Item{ID: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Create(ctx, storage.Item{ID: "x"}); !errors.Is(err, storage.ErrConflict) {
+		t.Fatalf("duplicate: got %v", err)
+	}
+	if err := r.Delete(ctx, "y"); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("delete missing: got %v", err)
+	}
+}
+
+func TestItemRepoSort(t *testing.T) {
+	ctx := context.Background()
+	r := NewItemRepo()
+	for _, it := range []storage.Item{{ID: "1", Name: "pear"}, {ID: "2", Name: "apple"}, {ID: "3", Name: "fig"}} {
+		if _, err := r.Create(ctx, it); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for sort, want := range map[storage.Sort]string{
+		storage.SortName:     "2 3 1",
+		storage.SortNameDesc: "1 3 2",
+		storage.SortID:       "1 2 3",
+		storage.SortOldest:   "1 2 3",
+		storage.SortNewest:   "3 2 1",
+	} {
+		items, _ := r.List(ctx, storage.ListQuery{Sort: sort})
+		got := ""
+		for i, item := range items {
+			if i > 0 {
+				got += " "
+			}
+			got += item.ID
+		}
+		if got != want {
+			t.Errorf("%s: got %s, want %s", sort, got, want)
+		}
+	}
+	if n, _ := r.Count(ctx, storage.ListQuery{}); n != 3 {
+		t.Errorf("count: got %d", n)
+	}
+}
+
+func TestItemRepoReplaceKeepsTimestamps(t *testing.T) {
+	ctx := context.Background()
+	r := NewItemRepo()
+	created, err := r.Replace(ctx, storage.Item{ID: "a", Name: "one"})
+	if err != nil || !created {
+		t.Fatalf("first replace: got %v, %v", created, err)
+	}
+	first, _ := r.Get(ctx, "a")
+	if first.CreatedAt == nil {
+		t.Fatal("created item has no created_at")
+	}
+	if err := r.Delete(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	created, err = r.Replace(ctx, storage.Item{ID: "a", Name: "two"})
+	if err != nil || created {
+		t.Fatalf("second replace: got %v, %v", created, err)
+	}
+	if _, err := r.Get(ctx, "a"); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("replace undeleted the item: got %v", err)
+	}
+	restored, err := r.Restore(ctx, "a")
+	if err != nil || restored.Name != "two" || !restored.CreatedAt.Equal(*first.CreatedAt) {
+		t.Fatalf("restored: got %+v, %v", restored, err)
+	}
+	if items, _ := r.GetMany(ctx, []string{"a", "b"}); len(items) != 1 || items[0].ID != "a" {
+		t.Fatalf("get many: got %+v", items)
+	}
+}
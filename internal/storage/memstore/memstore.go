@@ -0,0 +1,33 @@
+// Package memstore holds the in-process item stores. Store stands in for
+// Mongo on the /api/item routes when FALLBACK_MEMORY=true and Mongo cannot
+// be reached, keeping the demo usable with no external services at all;
+// ItemRepo is a storage.ItemRepository fake for tests.
+package memstore
+
+import (
+	"sync"
+
+	"multi-kind-app/internal/storage"
+)
+
+type Store struct {
+	mu    sync.RWMutex
+	items map[string]storage.Item
+}
+
+func New() *Store {
+	return &Store{items: map[string]storage.Item{}}
+}
+
+func (m *Store) Put(item storage.Item) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items[item.ID] = item
+}
+
+func (m *Store) Get(id string) (storage.Item, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	item, ok := m.items[id]
+	return item, ok
+}
@@ -0,0 +1,43 @@
+package memstore
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"multi-kind-app/internal/storage"
+)
+
+// UserRepo is a map-backed storage.UserRepository.
+type UserRepo struct {
+	mu    sync.RWMutex
+	users map[string]storage.User // by username
+}
+
+var _ storage.UserRepository = (*UserRepo)(nil)
+
+func NewUserRepo() *UserRepo {
+	return &UserRepo{users: map[string]storage.User{}}
+}
+
+func (r *UserRepo) Create(_ context.Context, u storage.User) (storage.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[u.Username]; ok {
+		return storage.User{}, storage.ErrConflict
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	u.CreatedAt = &now
+	r.users[u.Username] = u
+	return u, nil
+}
+
+func (r *UserRepo) GetByUsername(_ context.Context, username string) (storage.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	u, ok := r.users[username]
+	if !ok {
+		return storage.User{}, storage.ErrNotFound
+	}
+	return u, nil
+}
@@ -0,0 +1,314 @@
+package mongostore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"multi-kind-app/internal/storage"
+)
+
+// ItemRepo is the Mongo storage.ItemRepository over the items collection.
+type ItemRepo struct {
+	col func() (*mongo.Collection, error)
+}
+
+var (
+	_ storage.ItemRepository = (*ItemRepo)(nil)
+	_ storage.BulkCreator    = (*ItemRepo)(nil)
+	_ storage.BulkDeleter    = (*ItemRepo)(nil)
+	_ storage.Upserter       = (*ItemRepo)(nil)
+	_ storage.Getter         = (*ItemRepo)(nil)
+	_ storage.Replacer       = (*ItemRepo)(nil)
+)
+
+// NewItemRepo returns a repository that resolves its collection through col
+// on every call, so a reconnect is picked up without rebuilding the repo.
+func NewItemRepo(col func() (*mongo.Collection, error)) *ItemRepo {
+	return &ItemRepo{col: col}
+}
+
+func (r *ItemRepo) collection() (*mongo.Collection, error) {
+	c, err := r.col()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storage.ErrUnavailable, err)
+	}
+	return c, nil
+}
+
+// newestFirst orders by creation time; documents without created_at sort
+// after every one that has it.
+var newestFirst = SortSpec(storage.SortNewest)
+
+// SortSpec is the Find sort for s, ties broken by _id in the same
+// direction.
+func SortSpec(s storage.Sort) bson.D {
+	field, desc := s.Field()
+	dir := 1
+	if desc {
+		dir = -1
+	}
+	if field == "id" {
+		return bson.D{{Key: "_id", Value: dir}}
+	}
+	return bson.D{{Key: field, Value: dir}, {Key: "_id", Value: dir}}
+}
+
+// Filter is the Find filter for q's filters. Soft-deleted documents are
+// left out unless q.IncludeDeleted.
+func Filter(q storage.ListQuery) bson.M {
+	filter := bson.M{}
+	if !q.IncludeDeleted {
+		filter["deleted_at"] = bson.M{"$exists": false}
+	}
+	if q.Name != "" {
+		filter["name"] = q.Name
+	}
+	created := bson.M{}
+	if !q.CreatedAfter.IsZero() {
+		created["$gt"] = q.CreatedAfter
+	}
+	if !q.CreatedBefore.IsZero() {
+		created["$lt"] = q.CreatedBefore
+	}
+	if len(created) > 0 {
+		filter["created_at"] = created
+	}
+	return filter
+}
+
+func (r *ItemRepo) Create(ctx context.Context, item storage.Item) (storage.Item, error) {
+	col, err := r.collection()
+	if err != nil {
+		return storage.Item{}, err
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	item.CreatedAt = &now
+	if _, err := col.InsertOne(ctx, item); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return storage.Item{}, storage.ErrConflict
+		}
+		return storage.Item{}, err
+	}
+	return item, nil
+}
+
+// CreateMany is one unordered InsertMany, so a duplicate id fails only
+// its own item.
+func (r *ItemRepo) CreateMany(ctx context.Context, items []storage.Item) ([]storage.Item, []error) {
+	created := make([]storage.Item, len(items))
+	errs := make([]error, len(items))
+	col, err := r.collection()
+	if err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		return created, errs
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	docs := make([]any, len(items))
+	for i, item := range items {
+		item.CreatedAt = &now
+		created[i], docs[i] = item, item
+	}
+	_, err = col.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	var bwe mongo.BulkWriteException
+	switch {
+	case err == nil:
+	case errors.As(err, &bwe) && bwe.WriteConcernError == nil:
+		for _, we := range bwe.WriteErrors {
+			errs[we.Index] = we
+			if mongo.IsDuplicateKeyError(we) {
+				errs[we.Index] = storage.ErrConflict
+			}
+		}
+	default:
+		for i := range errs {
+			errs[i] = err
+		}
+	}
+	return created, errs
+}
+
+// UpsertByName is one FindOneAndUpdate with upsert: the value is $set on
+// the oldest live item with the name, and the rest of item only lands
+// through $setOnInsert. item.ID must be fresh, since the stored item
+// carrying it is how an insert is told apart from an update.
+func (r *ItemRepo) UpsertByName(ctx context.Context, item storage.Item) (storage.Item, bool, error) {
+	col, err := r.collection()
+	if err != nil {
+		return storage.Item{}, false, err
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	filter := bson.M{"name": item.Name, "deleted_at": bson.M{"$exists": false}}
+	update := bson.M{
+		"$set":         bson.M{"value": item.Value},
+		"$setOnInsert": bson.M{"_id": item.ID, "created_at": now},
+	}
+	opts := options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetSort(SortSpec(storage.SortOldest)).
+		SetReturnDocument(options.After)
+	var stored storage.Item
+	err = col.FindOneAndUpdate(ctx, filter, update, opts).Decode(&stored)
+	if mongo.IsDuplicateKeyError(err) {
+		return storage.Item{}, false, storage.ErrConflict
+	}
+	if err != nil {
+		return storage.Item{}, false, err
+	}
+	return stored, stored.ID == item.ID, nil
+}
+
+func (r *ItemRepo) Get(ctx context.Context, id string) (storage.Item, error) {
+	col, err := r.collection()
+	if err != nil {
+		return storage.Item{}, err
+	}
+	var item storage.Item
+	err = col.FindOne(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$exists": false}}).Decode(&item)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return storage.Item{}, storage.ErrNotFound
+	}
+	return item, err
+}
+
+// GetMany is one Find with $in.
+func (r *ItemRepo) GetMany(ctx context.Context, ids []string) ([]storage.Item, error) {
+	col, err := r.collection()
+	if err != nil {
+		return nil, err
+	}
+	cur, err := col.Find(ctx, bson.M{"_id": bson.M{"$in": ids}, "deleted_at": bson.M{"$exists": false}})
+	if err != nil {
+		return nil, err
+	}
+	items := []storage.Item{}
+	if err := cur.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Replace is one upserting UpdateOne whose pipeline swaps in item but
+// carries the stored created_at and deleted_at over. A document without
+// created_at, new or written before it existed, gets the current time.
+func (r *ItemRepo) Replace(ctx context.Context, item storage.Item) (bool, error) {
+	col, err := r.collection()
+	if err != nil {
+		return false, err
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	item.CreatedAt, item.DeletedAt = nil, nil
+	replace := bson.A{bson.M{"$replaceWith": bson.M{"$mergeObjects": bson.A{
+		bson.M{"$literal": item},
+		bson.M{"created_at": bson.M{"$ifNull": bson.A{"$created_at", now}}, "deleted_at": "$deleted_at"},
+	}}}}
+	res, err := col.UpdateOne(ctx, bson.M{"_id": item.ID}, replace, options.Update().SetUpsert(true))
+	if err != nil {
+		return false, err
+	}
+	return res.UpsertedCount > 0, nil
+}
+
+func (r *ItemRepo) GetLatest(ctx context.Context) (storage.Item, error) {
+	col, err := r.collection()
+	if err != nil {
+		return storage.Item{}, err
+	}
+	var item storage.Item
+	err = col.FindOne(ctx, Filter(storage.ListQuery{}), options.FindOne().SetSort(newestFirst)).Decode(&item)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return storage.Item{}, storage.ErrNotFound
+	}
+	return item, err
+}
+
+func (r *ItemRepo) List(ctx context.Context, q storage.ListQuery) ([]storage.Item, error) {
+	col, err := r.collection()
+	if err != nil {
+		return nil, err
+	}
+	opts := options.Find().SetSort(SortSpec(q.Sort)).SetSkip(q.Offset).SetLimit(q.Limit)
+	cur, err := col.Find(ctx, Filter(q), opts)
+	if err != nil {
+		return nil, err
+	}
+	items := []storage.Item{}
+	if err := cur.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *ItemRepo) Count(ctx context.Context, q storage.ListQuery) (int64, error) {
+	col, err := r.collection()
+	if err != nil {
+		return 0, err
+	}
+	return col.CountDocuments(ctx, Filter(q))
+}
+
+// Delete sets deleted_at on the document; it stays in the collection.
+func (r *ItemRepo) Delete(ctx context.Context, id string) error {
+	col, err := r.collection()
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	res, err := col.UpdateOne(ctx,
+		bson.M{"_id": id, "deleted_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"deleted_at": now}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+func (r *ItemRepo) Restore(ctx context.Context, id string) (storage.Item, error) {
+	col, err := r.collection()
+	if err != nil {
+		return storage.Item{}, err
+	}
+	var item storage.Item
+	err = col.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "deleted_at": bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{"deleted_at": ""}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&item)
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return item, err
+	}
+	// Tell a live item apart from a missing one.
+	n, err := col.CountDocuments(ctx, bson.M{"_id": id}, options.Count().SetLimit(1))
+	switch {
+	case err != nil:
+		return storage.Item{}, err
+	case n > 0:
+		return storage.Item{}, storage.ErrConflict
+	}
+	return storage.Item{}, storage.ErrNotFound
+}
+
+// DeleteMany is one UpdateMany setting deleted_at over Filter(q).
+func (r *ItemRepo) DeleteMany(ctx context.Context, q storage.ListQuery) (int64, error) {
+	col, err := r.collection()
+	if err != nil {
+		return 0, err
+	}
+	q.IncludeDeleted = false
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	res, err := col.UpdateMany(ctx, Filter(q), bson.M{"$set": bson.M{"deleted_at": now}})
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
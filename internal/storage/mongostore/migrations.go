@@ -0,0 +1,120 @@
+package mongostore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ──────────── Schema Migrations ────────────
+
+type migration struct {
+	Version int
+	Name    string
+	Up      func(ctx context.Context, db *mongo.Database) error
+}
+
+// migrations are applied in order and recorded in schema_migrations. Append
+// new ones at the end; never renumber or edit one that has shipped.
+var migrations = []migration{
+	{1, "items_name_index", func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection("items").Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys: bson.D{{Key: "name", Value: 1}},
+		})
+		return err
+	}},
+	{2, "audit_at_index", func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection("audit").Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys: bson.D{{Key: "at", Value: -1}},
+		})
+		return err
+	}},
+	{3, "diff_name_unique", func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection("diff").Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		})
+		return err
+	}},
+	{4, "snapshots_capped", func(ctx context.Context, db *mongo.Database) error {
+		// The cap bounds the collection's total size (and, when created
+		// here, its document count) across all routes; the snapshot
+		// middleware trims each route to its own limit. The middleware
+		// creates snapshots, uncapped, on its first insert if it ran before
+		// this migration; convert that one in place. convertToCapped takes
+		// only a size, so it keeps no document cap.
+		const size = 16 << 20
+		opts := options.CreateCollection().SetCapped(true).SetSizeInBytes(size).SetMaxDocuments(1000)
+		err := db.CreateCollection(ctx, "snapshots", opts)
+		var cmdErr mongo.CommandError
+		if errors.As(err, &cmdErr) && cmdErr.Code == 48 { // NamespaceExists
+			err = db.RunCommand(ctx, bson.D{{Key: "convertToCapped", Value: "snapshots"}, {Key: "size", Value: size}}).Err()
+		}
+		if err != nil {
+			return err
+		}
+		_, err = db.Collection("snapshots").Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys: bson.D{{Key: "route", Value: 1}},
+		})
+		return err
+	}},
+	{5, "users_username_unique", func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection("users").Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "username", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		})
+		return err
+	}},
+}
+
+// migrateMu serialises runs within this process; a concurrent run elsewhere
+// fails on the duplicate schema_migrations _id instead of applying twice.
+var migrateMu sync.Mutex
+
+// Migrate applies every migration newer than the recorded schema
+// version and returns the versions it applied. It stops at the first failure.
+func Migrate(ctx context.Context, db *mongo.Database) ([]int, error) {
+	migrateMu.Lock()
+	defer migrateMu.Unlock()
+
+	current, err := SchemaVersion(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	applied := []int{}
+	for _, m := range migrations {
+		if m.Version <= current {
+			continue
+		}
+		if err := m.Up(ctx, db); err != nil {
+			return applied, fmt.Errorf("migration %d (%s): %w", m.Version, m.Name, err)
+		}
+		rec := bson.M{"_id": m.Version, "name": m.Name, "applied_at": time.Now().UTC()}
+		if _, err := db.Collection("schema_migrations").InsertOne(ctx, rec); err != nil {
+			return applied, fmt.Errorf("record migration %d: %w", m.Version, err)
+		}
+		slog.Info("migration applied", "version", m.Version, "name", m.Name)
+		applied = append(applied, m.Version)
+	}
+	return applied, nil
+}
+
+// SchemaVersion returns the highest applied migration version, or 0.
+func SchemaVersion(ctx context.Context, db *mongo.Database) (int, error) {
+	var rec struct {
+		Version int `bson:"_id"`
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "_id", Value: -1}})
+	err := db.Collection("schema_migrations").FindOne(ctx, bson.M{}, opts).Decode(&rec)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, nil
+	}
+	return rec.Version, err
+}
@@ -0,0 +1,56 @@
+// Package mongostore opens the Mongo database the app stores items in and
+// owns its schema migrations.
+package mongostore
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"multi-kind-app/internal/config"
+	"multi-kind-app/internal/storage"
+)
+
+// Database is the database every collection lives in.
+const Database = "multikind"
+
+// Options builds client options from the config: the URI (or unix socket),
+// the pool size, the per-operation timeout and the command monitor. When the in-memory
+// fallback is on, server selection gives up after 2s instead of the
+// default 30s.
+func Options(c *config.Config, monitor *event.CommandMonitor) (*options.ClientOptions, error) {
+	uri := c.MongoURI
+	if sock := c.MongoSocket; sock != "" {
+		if err := storage.CheckSocket(sock); err != nil {
+			return nil, err
+		}
+		uri = "mongodb://" + url.PathEscape(sock)
+	}
+	opts := options.Client().ApplyURI(uri).
+		SetTimeout(time.Duration(c.MongoTimeout)).
+		SetMonitor(monitor)
+	if c.MongoMaxPoolSize > 0 {
+		opts.SetMaxPoolSize(uint64(c.MongoMaxPoolSize))
+	}
+	if c.MongoReplicaSet != "" {
+		opts.SetReplicaSet(c.MongoReplicaSet)
+	}
+	if c.FallbackMemory {
+		opts.SetServerSelectionTimeout(2 * time.Second)
+	}
+	return opts, nil
+}
+
+// Open connects a client and returns its app database. The driver dials
+// lazily, so this only fails on bad options.
+func Open(opts *options.ClientOptions) (*mongo.Database, error) {
+	client, err := mongo.Connect(context.Background(), opts)
+	if err != nil {
+		return nil, err
+	}
+	return client.Database(Database), nil
+}
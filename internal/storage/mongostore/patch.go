@@ -0,0 +1,53 @@
+package mongostore
+
+import (
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// MergePatch translates an RFC 7386 JSON Merge Patch into an update
+// document: null removes a field ($unset), a nested object is merged field
+// by field through dotted paths, and anything else replaces the field
+// ($set). An empty patch yields an empty update.
+//
+// Keys containing "." or starting with "$" would be read by Mongo as
+// paths or operators, so they are rejected.
+func MergePatch(patch map[string]any) (bson.M, error) {
+	set, unset := bson.M{}, bson.M{}
+	if err := mergePatch("", patch, set, unset); err != nil {
+		return nil, err
+	}
+	update := bson.M{}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	return update, nil
+}
+
+func mergePatch(prefix string, patch map[string]any, set, unset bson.M) error {
+	for k, v := range patch {
+		if k == "" || strings.Contains(k, ".") || strings.HasPrefix(k, "$") {
+			return fmt.Errorf("invalid field name %q", prefix+k)
+		}
+		path := prefix + k
+		switch v := v.(type) {
+		case nil:
+			unset[path] = ""
+		case map[string]any:
+			// An empty object changes nothing here. Strictly it should also
+			// turn a missing or non-object field into {}, but that needs
+			// the current value, which a single update can't read.
+			if err := mergePatch(path+".", v, set, unset); err != nil {
+				return err
+			}
+		default:
+			set[path] = v
+		}
+	}
+	return nil
+}
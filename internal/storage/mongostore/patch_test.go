@@ -0,0 +1,37 @@
+package mongostore
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestMergePatch(t *testing.T) {
+	got, err := MergePatch(map[string]any{
+		"name":  "pear",
+		"value": nil,
+		"meta":  map[string]any{"color": "green", "old": nil, "dims": map[string]any{"w": 2.0}},
+		"tags":  []any{"a"},
+		"empty": map[string]any{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := bson.M{
+		"$set":   bson.M{"name": "pear", "meta.color": "green", "meta.dims.w": 2.0, "tags": []any{"a"}},
+		"$unset": bson.M{"value": "", "meta.old": ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if got, _ := MergePatch(map[string]any{}); len(got) != 0 {
+		t.Fatalf("empty patch: got %v", got)
+	}
+	for _, bad := range []map[string]any{{"a.b": 1}, {"$set": 1}, {"x": map[string]any{"$inc": 1}}} {
+		if _, err := MergePatch(bad); err == nil {
+			t.Errorf("%v: want an error", bad)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package mongostore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"multi-kind-app/internal/storage"
+)
+
+// UserRepo is the Mongo storage.UserRepository over the users collection.
+// Username uniqueness is enforced by the users_username_unique index.
+type UserRepo struct {
+	db func() (*mongo.Database, error)
+}
+
+var _ storage.UserRepository = (*UserRepo)(nil)
+
+// NewUserRepo returns a repository that resolves its database through db
+// on every call, like NewItemRepo.
+func NewUserRepo(db func() (*mongo.Database, error)) *UserRepo {
+	return &UserRepo{db: db}
+}
+
+func (r *UserRepo) collection() (*mongo.Collection, error) {
+	db, err := r.db()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storage.ErrUnavailable, err)
+	}
+	return db.Collection("users"), nil
+}
+
+func (r *UserRepo) Create(ctx context.Context, u storage.User) (storage.User, error) {
+	col, err := r.collection()
+	if err != nil {
+		return storage.User{}, err
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	u.CreatedAt = &now
+	if _, err := col.InsertOne(ctx, u); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return storage.User{}, storage.ErrConflict
+		}
+		return storage.User{}, err
+	}
+	return u, nil
+}
+
+func (r *UserRepo) GetByUsername(ctx context.Context, username string) (storage.User, error) {
+	col, err := r.collection()
+	if err != nil {
+		return storage.User{}, err
+	}
+	var u storage.User
+	err = col.FindOne(ctx, bson.M{"username": username}).Decode(&u)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return storage.User{}, storage.ErrNotFound
+	}
+	return u, err
+}
@@ -0,0 +1,365 @@
+package redisstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"multi-kind-app/internal/storage"
+)
+
+// Keys used by ItemRepo: one hash per item plus a sorted set of live ids
+// scored by creation time in unix milliseconds, which orders List and
+// GetLatest. Delete moves an id, score and all, to the deleted index.
+const (
+	itemKeyPrefix   = "repo:item:"
+	itemIndexKey    = "repo:items"
+	deletedIndexKey = "repo:items:deleted"
+)
+
+// ItemRepo is the Redis storage.ItemRepository.
+type ItemRepo struct {
+	client func() (*redis.Client, error)
+}
+
+var (
+	_ storage.ItemRepository = (*ItemRepo)(nil)
+	_ storage.BulkCreator    = (*ItemRepo)(nil)
+	_ storage.BulkDeleter    = (*ItemRepo)(nil)
+)
+
+// NewItemRepo returns a repository that resolves its client through client
+// on every call, so a pool reset is picked up without rebuilding the repo.
+func NewItemRepo(client func() (*redis.Client, error)) *ItemRepo {
+	return &ItemRepo{client: client}
+}
+
+func (r *ItemRepo) redis() (*redis.Client, error) {
+	c, err := r.client()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", storage.ErrUnavailable, err)
+	}
+	return c, nil
+}
+
+func (r *ItemRepo) Create(ctx context.Context, item storage.Item) (storage.Item, error) {
+	rdb, err := r.redis()
+	if err != nil {
+		return storage.Item{}, err
+	}
+	// A soft-deleted item still owns its id.
+	switch err := rdb.ZScore(ctx, deletedIndexKey, item.ID).Err(); {
+	case err == nil:
+		return storage.Item{}, storage.ErrConflict
+	case !errors.Is(err, redis.Nil):
+		return storage.Item{}, err
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	item.CreatedAt = &now
+	// Claiming the id in the index first makes Create fail cleanly when two
+	// callers race for the same id.
+	added, err := rdb.ZAddNX(ctx, itemIndexKey, redis.Z{Score: float64(now.UnixMilli()), Member: item.ID}).Result()
+	if err != nil {
+		return storage.Item{}, err
+	}
+	if added == 0 {
+		return storage.Item{}, storage.ErrConflict
+	}
+	err = rdb.HSet(ctx, itemKeyPrefix+item.ID,
+		"name", item.Name, "value", item.Value, "created_at", now.Format(time.RFC3339Nano)).Err()
+	if err != nil {
+		rdb.ZRem(context.WithoutCancel(ctx), itemIndexKey, item.ID)
+		return storage.Item{}, err
+	}
+	return item, nil
+}
+
+// CreateMany rules out soft-deleted ids in one pipeline, claims the rest
+// in a second, then writes the hashes of the claimed ones in a third.
+func (r *ItemRepo) CreateMany(ctx context.Context, items []storage.Item) ([]storage.Item, []error) {
+	created := make([]storage.Item, len(items))
+	errs := make([]error, len(items))
+	fail := func(err error) ([]storage.Item, []error) {
+		for i := range errs {
+			if errs[i] == nil {
+				errs[i] = err
+			}
+		}
+		return created, errs
+	}
+	rdb, err := r.redis()
+	if err != nil {
+		return fail(err)
+	}
+	dead := make([]*redis.FloatCmd, len(items))
+	_, err = rdb.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for i, item := range items {
+			dead[i] = p.ZScore(ctx, deletedIndexKey, item.ID)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return fail(err)
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	claims := make([]*redis.IntCmd, len(items))
+	_, err = rdb.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for i, item := range items {
+			if dead[i].Err() == nil {
+				errs[i] = storage.ErrConflict
+				continue
+			}
+			claims[i] = p.ZAddNX(ctx, itemIndexKey, redis.Z{Score: float64(now.UnixMilli()), Member: item.ID})
+		}
+		return nil
+	})
+	if err != nil {
+		return fail(err)
+	}
+	_, err = rdb.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for i, item := range items {
+			if claims[i] == nil {
+				continue
+			}
+			if claims[i].Val() == 0 {
+				errs[i] = storage.ErrConflict
+				continue
+			}
+			item.CreatedAt = &now
+			created[i] = item
+			p.HSet(ctx, itemKeyPrefix+item.ID,
+				"name", item.Name, "value", item.Value, "created_at", now.Format(time.RFC3339Nano))
+		}
+		return nil
+	})
+	if err != nil {
+		// Release the claims so the ids can be retried.
+		for i, item := range items {
+			if errs[i] == nil {
+				rdb.ZRem(context.WithoutCancel(ctx), itemIndexKey, item.ID)
+			}
+		}
+		return fail(err)
+	}
+	return created, errs
+}
+
+func (r *ItemRepo) GetLatest(ctx context.Context) (storage.Item, error) {
+	items, err := r.List(ctx, storage.ListQuery{Limit: 1})
+	if err != nil {
+		return storage.Item{}, err
+	}
+	if len(items) == 0 {
+		return storage.Item{}, storage.ErrNotFound
+	}
+	return items[0], nil
+}
+
+// scoreRange is the index score range for q's created bounds, both
+// exclusive, or an error for filters the index can't serve.
+func scoreRange(q storage.ListQuery) (lo, hi string, err error) {
+	if q.Name != "" {
+		return "", "", fmt.Errorf("%w: the redis store can't filter by name", storage.ErrInvalidQuery)
+	}
+	if q.IncludeDeleted {
+		return "", "", fmt.Errorf("%w: the redis store can't list deleted items", storage.ErrInvalidQuery)
+	}
+	lo, hi = "-inf", "+inf"
+	if !q.CreatedAfter.IsZero() {
+		lo = "(" + strconv.FormatInt(q.CreatedAfter.UnixMilli(), 10)
+	}
+	if !q.CreatedBefore.IsZero() {
+		hi = "(" + strconv.FormatInt(q.CreatedBefore.UnixMilli(), 10)
+	}
+	return lo, hi, nil
+}
+
+// List pages over the creation-time index, so only the created_at sorts
+// and filters are supported.
+func (r *ItemRepo) List(ctx context.Context, q storage.ListQuery) ([]storage.Item, error) {
+	field, desc := q.Sort.Field()
+	if field != "created_at" {
+		return nil, fmt.Errorf("%w: the redis store sorts by created_at only", storage.ErrInvalidQuery)
+	}
+	lo, hi, err := scoreRange(q)
+	if err != nil {
+		return nil, err
+	}
+	rdb, err := r.redis()
+	if err != nil {
+		return nil, err
+	}
+	count := q.Limit
+	if count == 0 {
+		count = -1
+	}
+	ids, err := rdb.ZRangeArgs(ctx, redis.ZRangeArgs{
+		Key: itemIndexKey, Start: lo, Stop: hi, ByScore: true, Rev: desc,
+		Offset: q.Offset, Count: count,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	pipe := rdb.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(ids))
+	for i, id := range ids {
+		cmds[i] = pipe.HGetAll(ctx, itemKeyPrefix+id)
+	}
+	if len(ids) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, err
+		}
+	}
+	items := make([]storage.Item, 0, len(ids))
+	for i, cmd := range cmds {
+		h := cmd.Val()
+		if len(h) == 0 {
+			continue // hash deleted out from under the index
+		}
+		items = append(items, decodeItem(ids[i], h))
+	}
+	return items, nil
+}
+
+func (r *ItemRepo) Count(ctx context.Context, q storage.ListQuery) (int64, error) {
+	lo, hi, err := scoreRange(q)
+	if err != nil {
+		return 0, err
+	}
+	rdb, err := r.redis()
+	if err != nil {
+		return 0, err
+	}
+	return rdb.ZCount(ctx, itemIndexKey, lo, hi).Result()
+}
+
+// markDeleted queues the move of id from the live index to the deleted
+// one, stamping its hash with now.
+func markDeleted(ctx context.Context, p redis.Pipeliner, id string, score float64, now time.Time) *redis.IntCmd {
+	removed := p.ZRem(ctx, itemIndexKey, id)
+	p.ZAdd(ctx, deletedIndexKey, redis.Z{Score: score, Member: id})
+	p.HSet(ctx, itemKeyPrefix+id, "deleted_at", now.Format(time.RFC3339Nano))
+	return removed
+}
+
+func (r *ItemRepo) Delete(ctx context.Context, id string) error {
+	rdb, err := r.redis()
+	if err != nil {
+		return err
+	}
+	score, err := rdb.ZScore(ctx, itemIndexKey, id).Result()
+	if errors.Is(err, redis.Nil) {
+		return storage.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	var removed *redis.IntCmd
+	_, err = rdb.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		removed = markDeleted(ctx, p, id, score, now)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if removed.Val() == 0 {
+		return storage.ErrNotFound // a concurrent Delete got there first
+	}
+	return nil
+}
+
+func (r *ItemRepo) Restore(ctx context.Context, id string) (storage.Item, error) {
+	rdb, err := r.redis()
+	if err != nil {
+		return storage.Item{}, err
+	}
+	score, err := rdb.ZScore(ctx, deletedIndexKey, id).Result()
+	if errors.Is(err, redis.Nil) {
+		// Tell a live item apart from a missing one.
+		switch err := rdb.ZScore(ctx, itemIndexKey, id).Err(); {
+		case err == nil:
+			return storage.Item{}, storage.ErrConflict
+		case errors.Is(err, redis.Nil):
+			return storage.Item{}, storage.ErrNotFound
+		default:
+			return storage.Item{}, err
+		}
+	}
+	if err != nil {
+		return storage.Item{}, err
+	}
+	var (
+		removed *redis.IntCmd
+		h       *redis.MapStringStringCmd
+	)
+	_, err = rdb.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		removed = p.ZRem(ctx, deletedIndexKey, id)
+		p.ZAdd(ctx, itemIndexKey, redis.Z{Score: score, Member: id})
+		p.HDel(ctx, itemKeyPrefix+id, "deleted_at")
+		h = p.HGetAll(ctx, itemKeyPrefix+id)
+		return nil
+	})
+	if err != nil {
+		return storage.Item{}, err
+	}
+	if removed.Val() == 0 {
+		return storage.Item{}, storage.ErrConflict // a concurrent Restore got there first
+	}
+	return decodeItem(id, h.Val()), nil
+}
+
+// deleteBatch bounds how many ids DeleteMany moves per round trip.
+const deleteBatch = 500
+
+// DeleteMany walks the live index's score range in batches, moving each
+// id to the deleted index.
+func (r *ItemRepo) DeleteMany(ctx context.Context, q storage.ListQuery) (int64, error) {
+	q.IncludeDeleted = false
+	lo, hi, err := scoreRange(q)
+	if err != nil {
+		return 0, err
+	}
+	rdb, err := r.redis()
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	var deleted int64
+	for {
+		zs, err := rdb.ZRangeArgsWithScores(ctx, redis.ZRangeArgs{
+			Key: itemIndexKey, Start: lo, Stop: hi, ByScore: true, Count: deleteBatch,
+		}).Result()
+		if err != nil || len(zs) == 0 {
+			return deleted, err
+		}
+		removed := make([]*redis.IntCmd, len(zs))
+		_, err = rdb.TxPipelined(ctx, func(p redis.Pipeliner) error {
+			for i, z := range zs {
+				removed[i] = markDeleted(ctx, p, z.Member.(string), z.Score, now)
+			}
+			return nil
+		})
+		if err != nil {
+			return deleted, err
+		}
+		for _, cmd := range removed {
+			deleted += cmd.Val()
+		}
+	}
+}
+
+func decodeItem(id string, h map[string]string) storage.Item {
+	item := storage.Item{ID: id, Name: h["name"], Value: h["value"]}
+	if t, err := time.Parse(time.RFC3339Nano, h["created_at"]); err == nil {
+		item.CreatedAt = &t
+	}
+	if t, err := time.Parse(time.RFC3339Nano, h["deleted_at"]); err == nil {
+		item.DeletedAt = &t
+	}
+	return item
+}
@@ -0,0 +1,67 @@
+// Package redisstore builds the app's Redis clients.
+package redisstore
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"multi-kind-app/internal/config"
+	"multi-kind-app/internal/storage"
+)
+
+// Options returns the primary client's options: REDIS_ADDR, or the unix
+// socket at REDIS_SOCKET when set, with REDIS_POOL_SIZE connections.
+func Options(c *config.Config) (*redis.Options, error) {
+	opts := &redis.Options{Addr: c.RedisAddr, PoolSize: c.RedisPoolSize}
+	if sock := c.RedisSocket; sock != "" {
+		if err := storage.CheckSocket(sock); err != nil {
+			return nil, err
+		}
+		opts.Network, opts.Addr = "unix", sock
+	}
+	return opts, nil
+}
+
+// New builds a client whose commands each run under timeout, followed by
+// any extra hooks in order.
+func New(opts *redis.Options, timeout time.Duration, hooks ...redis.Hook) *redis.Client {
+	c := redis.NewClient(opts)
+	c.AddHook(TimeoutHook{timeout})
+	for _, h := range hooks {
+		c.AddHook(h)
+	}
+	return c
+}
+
+// ──────────── Per-Backend Timeouts ────────────
+
+// TimeoutHook derives a context.WithTimeout child for every Redis command,
+// so the Redis deadline applies no matter which handler issued it.
+type TimeoutHook struct {
+	D time.Duration
+}
+
+func (h TimeoutHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (h TimeoutHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, cancel := context.WithTimeout(ctx, h.D)
+		defer cancel()
+		return next(ctx, cmd)
+	}
+}
+
+func (h TimeoutHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, cancel := context.WithTimeout(ctx, h.D)
+		defer cancel()
+		return next(ctx, cmds)
+	}
+}
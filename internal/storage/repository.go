@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ItemRepository is the storage contract the item handlers depend on. Each
+// backend package provides an implementation; tests can supply their own.
+type ItemRepository interface {
+	// Create stores a new item. It fails with ErrConflict if the id is
+	// already taken, and sets CreatedAt on the returned copy.
+	Create(ctx context.Context, item Item) (Item, error)
+	// GetLatest returns the most recently created live item, or
+	// ErrNotFound.
+	GetLatest(ctx context.Context) (Item, error)
+	// List returns a page of items in q.Sort order, newest first by
+	// default. A Sort the backend can't serve fails with ErrInvalidQuery.
+	List(ctx context.Context, q ListQuery) ([]Item, error)
+	// Count returns how many items a List with q would page over; q's
+	// Limit, Offset and Sort are ignored.
+	Count(ctx context.Context, q ListQuery) (int64, error)
+	// Delete soft-deletes a live item by id, or returns ErrNotFound. The
+	// item keeps its id, so Create can't reuse it until it's restored.
+	Delete(ctx context.Context, id string) error
+	// Restore undeletes a soft-deleted item. It returns ErrNotFound if
+	// there is no such item and ErrConflict if it isn't deleted.
+	Restore(ctx context.Context, id string) (Item, error)
+}
+
+// BulkCreator is implemented by repositories that can create many items
+// in fewer round trips than one Create each. The results are index-aligned
+// with items: errs[i] is nil when items[i] was created as created[i], and
+// otherwise fails the way Create would.
+type BulkCreator interface {
+	CreateMany(ctx context.Context, items []Item) (created []Item, errs []error)
+}
+
+// BulkDeleter is implemented by repositories that can soft-delete every
+// live item matching q's filters at once. q's Limit, Offset, Sort and
+// IncludeDeleted are ignored.
+type BulkDeleter interface {
+	DeleteMany(ctx context.Context, q ListQuery) (int64, error)
+}
+
+// Upserter is implemented by repositories that can write an item by name
+// in one step: the oldest live item named item.Name takes item.Value, or
+// item is created as Create would when there is none. created says which
+// happened.
+type Upserter interface {
+	UpsertByName(ctx context.Context, item Item) (stored Item, created bool, err error)
+}
+
+// Getter is implemented by repositories that can look items up by id. Get
+// returns the live item or ErrNotFound; GetMany returns the live items
+// among ids, in no particular order, and leaves out the rest.
+type Getter interface {
+	Get(ctx context.Context, id string) (Item, error)
+	GetMany(ctx context.Context, ids []string) ([]Item, error)
+}
+
+// Replacer is implemented by repositories that can write an item whole,
+// creating it when its id is new. The stored CreatedAt and DeletedAt
+// survive a replace, so a soft-deleted item stays deleted. created says
+// whether the id was new.
+type Replacer interface {
+	Replace(ctx context.Context, item Item) (created bool, err error)
+}
+
+// ListQuery selects one page of a List.
+type ListQuery struct {
+	Limit  int64
+	Offset int64
+	Sort   Sort
+
+	// Filters; zero values match everything. Name matches exactly, and
+	// the created bounds are exclusive. Items with no CreatedAt never match
+	// a created bound.
+	Name          string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// IncludeDeleted also matches soft-deleted items.
+	IncludeDeleted bool
+}
+
+// Match reports whether item passes q's filters.
+func (q ListQuery) Match(item Item) bool {
+	if item.DeletedAt != nil && !q.IncludeDeleted {
+		return false
+	}
+	if q.Name != "" && item.Name != q.Name {
+		return false
+	}
+	if q.CreatedAfter.IsZero() && q.CreatedBefore.IsZero() {
+		return true
+	}
+	if item.CreatedAt == nil {
+		return false
+	}
+	return (q.CreatedAfter.IsZero() || item.CreatedAt.After(q.CreatedAfter)) &&
+		(q.CreatedBefore.IsZero() || item.CreatedAt.Before(q.CreatedBefore))
+}
+
+// Sort is a List order: a field name, prefixed with "-" for descending.
+// The zero Sort is SortNewest.
+type Sort string
+
+const (
+	SortNewest   Sort = "-created_at"
+	SortOldest   Sort = "created_at"
+	SortID       Sort = "id"
+	SortIDDesc   Sort = "-id"
+	SortName     Sort = "name"
+	SortNameDesc Sort = "-name"
+)
+
+// ParseSort validates a ?sort= value. An empty s is SortNewest.
+func ParseSort(s string) (Sort, error) {
+	switch sort := Sort(s); sort {
+	case "":
+		return SortNewest, nil
+	case SortNewest, SortOldest, SortID, SortIDDesc, SortName, SortNameDesc:
+		return sort, nil
+	}
+	return "", fmt.Errorf("%w: unknown sort %q (want [-]created_at, [-]id or [-]name)", ErrInvalidQuery, s)
+}
+
+// Field returns the field name and whether the order is descending.
+func (s Sort) Field() (field string, desc bool) {
+	if s == "" {
+		s = SortNewest
+	}
+	field, desc = strings.CutPrefix(string(s), "-")
+	return field, desc
+}
+
+var (
+	ErrNotFound = errors.New("item not found")
+	ErrConflict = errors.New("item already exists")
+	// ErrUnavailable wraps failures to obtain a backend client at all, as
+	// opposed to a failed operation on a working client.
+	ErrUnavailable = errors.New("backend unavailable")
+	// ErrInvalidQuery is a ListQuery the backend can't serve.
+	ErrInvalidQuery = errors.New("invalid query")
+)
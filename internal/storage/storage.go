@@ -0,0 +1,34 @@
+// Package storage holds the item model and helpers shared by the
+// per-backend store packages.
+package storage
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// CheckSocket verifies that path exists and is a Unix socket.
+func CheckSocket(path string) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if fi.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s is not a unix socket", path)
+	}
+	return nil
+}
+
+// Item is the record every backend stores.
+type Item struct {
+	ID    string `json:"id" bson:"_id"`
+	Name  string `json:"name" bson:"name"`
+	Value string `json:"value" bson:"value"`
+
+	// CreatedAt is set when a repository first stores the item; documents
+	// written before it existed don't carry it.
+	CreatedAt *time.Time `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	// DeletedAt marks an item soft-deleted by ItemRepository.Delete.
+	DeletedAt *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+}
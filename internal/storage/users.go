@@ -0,0 +1,23 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// User is a registered account. PasswordHash never leaves the server.
+type User struct {
+	ID           string     `json:"id" bson:"_id"`
+	Username     string     `json:"username" bson:"username"`
+	PasswordHash []byte     `json:"-" bson:"password_hash"`
+	CreatedAt    *time.Time `json:"created_at,omitempty" bson:"created_at,omitempty"`
+}
+
+// UserRepository stores users. Usernames are unique.
+type UserRepository interface {
+	// Create stores a new user, failing with ErrConflict if the username
+	// is taken, and sets CreatedAt on the returned copy.
+	Create(ctx context.Context, u User) (User, error)
+	// GetByUsername returns the user, or ErrNotFound.
+	GetByUsername(ctx context.Context, username string) (User, error)
+}